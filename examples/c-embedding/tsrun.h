@@ -188,6 +188,10 @@ TsRunResult tsrun_fulfill_orders(TsRunContext* ctx,
                                   const TsRunOrderResponse* responses,
                                   size_t count);
 
+// Reject an order with a value, thrown as is at the order() call
+// (unlike an error string, which reaches JS as a string)
+TsRunResult tsrun_reject_order(TsRunContext* ctx, TsRunOrderId order_id, TsRunValue* reason);
+
 // Create a pending order that will suspend the interpreter.
 // Use in native callbacks to perform async operations.
 // The payload is accessible via order.payload in the step result.
@@ -207,6 +211,9 @@ TsRunResult tsrun_resolve_promise(TsRunContext* ctx, TsRunValue* promise, TsRunV
 // Reject a promise
 TsRunResult tsrun_reject_promise(TsRunContext* ctx, TsRunValue* promise, const char* error);
 
+// Reject a promise with a value (e.g. from tsrun_error_new)
+TsRunResult tsrun_reject_promise_value(TsRunContext* ctx, TsRunValue* promise, TsRunValue* reason);
+
 // ============================================================================
 // Value Inspection
 // ============================================================================
@@ -221,6 +228,9 @@ bool tsrun_is_string(const TsRunValue* val);
 bool tsrun_is_object(const TsRunValue* val);
 bool tsrun_is_array(const TsRunValue* val);
 bool tsrun_is_function(const TsRunValue* val);
+bool tsrun_is_map(const TsRunValue* val);
+bool tsrun_is_set(const TsRunValue* val);
+bool tsrun_is_generator(const TsRunValue* val);  // Live (not completed) generator object
 
 // Extract primitive values (undefined behavior if wrong type - check first!)
 bool tsrun_get_bool(const TsRunValue* val);
@@ -246,6 +256,9 @@ TsRunValueResult tsrun_json_parse(TsRunContext* ctx, const char* json);
 TsRunValueResult tsrun_object_new(TsRunContext* ctx);
 TsRunValueResult tsrun_array_new(TsRunContext* ctx);
 
+// Create an Error object (inherits from the builtin Error.prototype)
+TsRunValueResult tsrun_error_new(TsRunContext* ctx, const char* name, const char* message);
+
 // ============================================================================
 // Value Memory Management
 // ============================================================================
@@ -267,6 +280,16 @@ TsRunResult tsrun_set(TsRunContext* ctx, TsRunValue* obj, const char* key, TsRun
 bool tsrun_has(TsRunContext* ctx, TsRunValue* obj, const char* key);
 TsRunResult tsrun_delete(TsRunContext* ctx, TsRunValue* obj, const char* key);
 
+// Map and Set entries as a new array: [key, value] pairs for a Map, values
+// for a Set (read directly, not through Symbol.iterator)
+TsRunValueResult tsrun_collection_entries(TsRunContext* ctx, TsRunValue* collection);
+
+// Object integrity (Object.freeze / Object.seal without the Object global)
+TsRunResult tsrun_freeze(TsRunContext* ctx, TsRunValue* obj);
+TsRunResult tsrun_seal(TsRunContext* ctx, TsRunValue* obj);
+bool tsrun_is_frozen(const TsRunValue* val);
+bool tsrun_is_sealed(const TsRunValue* val);
+
 // Get property keys (caller must free returned array with tsrun_free_strings)
 char** tsrun_keys(TsRunContext* ctx, TsRunValue* obj, size_t* count_out);
 void tsrun_free_strings(char** strings, size_t count);
@@ -302,6 +325,17 @@ TsRunValueResult tsrun_call_method(TsRunContext* ctx,
 TsRunValueResult tsrun_get_global(TsRunContext* ctx, const char* name);
 TsRunResult tsrun_set_global(TsRunContext* ctx, const char* name, TsRunValue* val);
 
+// ============================================================================
+// Symbol Registry
+// ============================================================================
+
+// Get or create the symbol registered under key (Symbol.for)
+TsRunValueResult tsrun_symbol_for(TsRunContext* ctx, const char* key);
+
+// Get the registry key of a symbol as a string, or undefined if the symbol
+// is not registered (Symbol.keyFor)
+TsRunValueResult tsrun_symbol_key_for(TsRunContext* ctx, const TsRunValue* sym);
+
 // ============================================================================
 // Module Exports
 // ============================================================================
@@ -0,0 +1,105 @@
+// Broadcast example: Cross-context messaging with BroadcastChannel.
+//
+// This example demonstrates how two interpreter contexts (e.g. workers in a
+// pool) communicate through BroadcastChannel. Context A listens for cache
+// invalidation messages; context B publishes one.
+//
+// Messages are delivered when the receiving context runs, so context A is
+// run again after B has posted.
+package main
+
+import (
+	"context"
+	"fmt"
+	"log"
+
+	"github.com/example/tsrun-go/tsrun"
+)
+
+func main() {
+	ctx := context.Background()
+
+	// Create runtime with the BroadcastChannel API enabled
+	rt, err := tsrun.New(ctx,
+		tsrun.ConsoleOption(func(level tsrun.ConsoleLevel, message string) {
+			fmt.Println(message)
+		}),
+		tsrun.WithBroadcastChannelAPI(),
+	)
+	if err != nil {
+		log.Fatalf("Failed to create runtime: %v", err)
+	}
+	defer rt.Close(ctx)
+
+	// Create two interpreter contexts
+	listener, err := rt.NewContext(ctx)
+	if err != nil {
+		log.Fatalf("Failed to create listener context: %v", err)
+	}
+	defer listener.Free(ctx)
+
+	publisher, err := rt.NewContext(ctx)
+	if err != nil {
+		log.Fatalf("Failed to create publisher context: %v", err)
+	}
+	defer publisher.Free(ctx)
+
+	fmt.Println("=== Context A: subscribing ===")
+	run(ctx, listener, `
+		const cache = new Map<string, string>([["user:42", "Alice"], ["user:7", "Bob"]]);
+
+		const channel = new BroadcastChannel("cache");
+		channel.onmessage = (event) => {
+			const { type, key } = event.data;
+			console.log("[A] received:", JSON.stringify(event.data));
+			if (type === "invalidate") {
+				cache.delete(key);
+				console.log("[A] cache keys:", Array.from(cache.keys()).join(", "));
+			}
+			channel.close();
+		};
+		console.log("[A] listening on 'cache'");
+	`, "/listener.ts")
+
+	fmt.Println()
+	fmt.Println("=== Context B: publishing ===")
+	run(ctx, publisher, `
+		const channel = new BroadcastChannel("cache");
+		channel.postMessage({ type: "invalidate", key: "user:42" });
+		console.log("[B] posted invalidation for user:42");
+		channel.close();
+	`, "/publisher.ts")
+
+	fmt.Println()
+	fmt.Println("=== Context A: delivering ===")
+	result, err := listener.Run(ctx)
+	if err != nil {
+		log.Fatalf("Run error: %v", err)
+	}
+	if result.Status == tsrun.StatusError {
+		log.Fatalf("Error: %s", result.Error)
+	}
+	fmt.Printf("Context A status: %s\n", result.Status)
+}
+
+// run prepares and runs code, printing the final status.
+func run(ctx context.Context, interp *tsrun.Context, code string, path string) {
+	if err := interp.Prepare(ctx, code, path); err != nil {
+		log.Fatalf("Prepare error: %v", err)
+	}
+
+	result, err := interp.Run(ctx)
+	if err != nil {
+		log.Fatalf("Run error: %v", err)
+	}
+
+	switch result.Status {
+	case tsrun.StatusError:
+		log.Fatalf("Error: %s", result.Error)
+	case tsrun.StatusComplete:
+		if result.Value != nil {
+			result.Value.Free(ctx)
+		}
+	}
+	fmt.Printf("Status: %s\n", result.Status)
+}
@@ -53,6 +53,9 @@ run_examples() {
 
     echo -e "\n=== Native Functions Example ==="
     go run ./native
+
+    echo -e "\n=== Broadcast Example ==="
+    go run ./broadcast
 }
 
 run_example() {
@@ -73,7 +76,7 @@ case "$ACTION" in
     --example)
         if [ -z "$2" ]; then
             echo "Usage: $0 --example <name>"
-            echo "Available: basic, modules, async, native"
+            echo "Available: basic, modules, async, native, broadcast"
             exit 1
         fi
         build_wasm && run_example "$2"
@@ -0,0 +1,251 @@
+package tsrun
+
+import (
+	"context"
+	"fmt"
+)
+
+// abortControllerShim implements AbortController and AbortSignal. It runs
+// as an anonymous script, so it does not become the context's main module,
+// and completes with a helpers object that only the bindings hold. Private
+// fields brand real signals and controllers, so scripts cannot spoof them
+// or redirect the bindings by replacing the globals.
+const abortControllerShim = `
+(() => {
+	const internal = Symbol("AbortSignal");
+
+	let isSignal: (value: any) => boolean;
+	let signalAbort: (signal: AbortSignal, reason: any) => void;
+	let controllerAbort: (controller: any, reason: any) => void;
+
+	function abortError(): Error {
+		const error = new Error("This operation was aborted");
+		error.name = "AbortError";
+		return error;
+	}
+
+	class AbortSignal {
+		#aborted = false;
+		#reason: any = undefined;
+		#listeners: Array<(event: any) => void> = [];
+		onabort: ((event: any) => void) | null = null;
+
+		constructor(key?: symbol) {
+			if (key !== internal) throw new TypeError("Illegal constructor");
+		}
+
+		get aborted(): boolean {
+			return this.#aborted;
+		}
+
+		get reason(): any {
+			return this.#reason;
+		}
+
+		addEventListener(type: string, listener: (event: any) => void): void {
+			if (type !== "abort" || typeof listener !== "function") return;
+			if (!this.#listeners.includes(listener)) this.#listeners.push(listener);
+		}
+
+		removeEventListener(type: string, listener: (event: any) => void): void {
+			if (type !== "abort") return;
+			const index = this.#listeners.indexOf(listener);
+			if (index >= 0) this.#listeners.splice(index, 1);
+		}
+
+		throwIfAborted(): void {
+			if (this.#aborted) throw this.#reason;
+		}
+
+		static abort(reason?: any): AbortSignal {
+			const signal = new AbortSignal(internal);
+			signalAbort(signal, reason);
+			return signal;
+		}
+
+		static {
+			isSignal = (value: any) => {
+				try {
+					value.#aborted;
+					return true;
+				} catch {
+					return false;
+				}
+			};
+
+			// Listeners all run; the first error is rethrown afterwards
+			signalAbort = (signal: AbortSignal, reason: any) => {
+				if (signal.#aborted) return;
+				signal.#aborted = true;
+				signal.#reason = reason === undefined ? abortError() : reason;
+
+				const event = { type: "abort", target: signal };
+				let failed = false;
+				let error: any;
+				for (const listener of [signal.onabort, ...signal.#listeners]) {
+					if (typeof listener !== "function") continue;
+					try {
+						listener.call(signal, event);
+					} catch (e) {
+						if (!failed) {
+							failed = true;
+							error = e;
+						}
+					}
+				}
+				if (failed) throw error;
+			};
+		}
+	}
+
+	class AbortController {
+		#signal = new AbortSignal(internal);
+
+		get signal(): AbortSignal {
+			return this.#signal;
+		}
+
+		abort(reason?: any): void {
+			signalAbort(this.#signal, reason);
+		}
+
+		static {
+			controllerAbort = (controller: any, reason: any) => {
+				let signal: AbortSignal;
+				try {
+					signal = controller.#signal;
+				} catch {
+					throw new TypeError("value is not an AbortController");
+				}
+				signalAbort(signal, reason);
+			};
+		}
+	}
+
+	globalThis.AbortController = AbortController;
+	globalThis.AbortSignal = AbortSignal;
+
+	return {
+		create: () => new AbortController(),
+		isSignal: (value: any) => isSignal(value),
+		abort: (controller: any, reason: any) => controllerAbort(controller, reason),
+	};
+})()
+`
+
+// WithAbortControllerAPI installs AbortController and AbortSignal globals in
+// every context created by the runtime, and enables NewAbortController,
+// IsAbortSignal, AbortWithReason and SetAbortController.
+//
+// Aborting runs the signal's listeners synchronously; promise reactions
+// they schedule run on the next Run or Step.
+func WithAbortControllerAPI() func(*Runtime) {
+	return func(r *Runtime) {
+		r.abortControllerAPI = true
+	}
+}
+
+// installAbortController runs the AbortController shim in the context and
+// keeps its helpers.
+func (c *Context) installAbortController(ctx context.Context) error {
+	if err := c.Prepare(ctx, abortControllerShim, ""); err != nil {
+		return err
+	}
+
+	result, err := c.Run(ctx)
+	if err != nil {
+		return err
+	}
+
+	switch {
+	case result.Status == StatusError:
+		return fmt.Errorf("abort controller setup error: %s", result.Error)
+	case result.Status != StatusComplete || result.Value == nil:
+		return fmt.Errorf("abort controller setup: unexpected status %s", result.Status)
+	}
+
+	c.abortHelpers = result.Value
+	return nil
+}
+
+// NewAbortController creates a new AbortController, as `new AbortController()`
+// would before any script replaced the global.
+func (c *Context) NewAbortController(ctx context.Context) (*Value, error) {
+	if c.abortHelpers == nil {
+		return nil, fmt.Errorf("AbortController API not enabled (see WithAbortControllerAPI)")
+	}
+	return c.callMethod(ctx, c.abortHelpers, "create")
+}
+
+// IsAbortSignal returns true if the value is an AbortSignal, such as the
+// signal property of an AbortController. Objects that only look like a
+// signal are not. Always false without WithAbortControllerAPI.
+func (v *Value) IsAbortSignal(ctx context.Context) bool {
+	if v.handle == 0 || v.ctx.abortHelpers == nil {
+		return false
+	}
+
+	result, err := v.ctx.callMethod(ctx, v.ctx.abortHelpers, "isSignal", v)
+	if err != nil {
+		return false
+	}
+	defer result.Free(ctx)
+
+	isSignal, _ := result.AsBool(ctx)
+	return isSignal
+}
+
+// AbortWithReason aborts controller with reason as the signal's reason, like
+// controller.abort(reason). Aborting an already aborted controller does
+// nothing. Returns an error if controller is not an AbortController or a
+// listener throws; all listeners run either way.
+func (c *Context) AbortWithReason(ctx context.Context, controller *Value, reason string) error {
+	if c.abortHelpers == nil {
+		return fmt.Errorf("AbortController API not enabled (see WithAbortControllerAPI)")
+	}
+	if controller == nil || controller.handle == 0 {
+		return fmt.Errorf("controller is nil")
+	}
+
+	reasonVal, err := c.String(ctx, reason)
+	if err != nil {
+		return err
+	}
+	defer reasonVal.Free(ctx)
+
+	result, err := c.callMethod(ctx, c.abortHelpers, "abort", controller, reasonVal)
+	if err != nil {
+		return err
+	}
+	return result.Free(ctx)
+}
+
+// SetAbortController designates controller to be aborted when the
+// context.Context passed to Run or Step is done, with the context error
+// ("context canceled" or "context deadline exceeded") as the reason. This
+// lets Go cancellation reach fetch-style code waiting on the signal.
+//
+// The check happens when Run or Step is called, so a host waiting on orders
+// should call Run once more after cancellation. The controller is aborted at
+// most once and must stay unfreed until then; pass nil to clear it.
+func (c *Context) SetAbortController(controller *Value) error {
+	if controller != nil && c.abortHelpers == nil {
+		return fmt.Errorf("AbortController API not enabled (see WithAbortControllerAPI)")
+	}
+	c.abortController = controller
+	return nil
+}
+
+// abortIfDone aborts the designated controller once ctx is done.
+func (c *Context) abortIfDone(ctx context.Context) error {
+	controller := c.abortController
+	if controller == nil || ctx.Err() == nil {
+		return nil
+	}
+
+	c.abortController = nil
+	if controller.handle == 0 {
+		return nil // Freed by the caller
+	}
+	return c.AbortWithReason(ctx, controller, ctx.Err().Error())
+}
@@ -0,0 +1,118 @@
+package tsrun
+
+import (
+	"context"
+	"testing"
+)
+
+func TestAbortController(t *testing.T) {
+	ctx, interp := newTestContext(t, WithAbortControllerAPI())
+
+	controller, err := interp.NewAbortController(ctx)
+	if err != nil {
+		t.Fatalf("NewAbortController: %v", err)
+	}
+	defer controller.Free(ctx)
+
+	signal, err := controller.Get(ctx, "signal")
+	if err != nil {
+		t.Fatalf("Get(signal): %v", err)
+	}
+	defer signal.Free(ctx)
+	if !signal.IsAbortSignal(ctx) {
+		t.Error("IsAbortSignal(controller.signal) = false, want true")
+	}
+	if controller.IsAbortSignal(ctx) {
+		t.Error("IsAbortSignal(controller) = true, want false")
+	}
+
+	lookalike := evalValue(t, ctx, interp, `({ aborted: false, reason: undefined, onabort: null, addEventListener() {} })`)
+	if lookalike.IsAbortSignal(ctx) {
+		t.Error("IsAbortSignal(look-alike) = true, want false")
+	}
+	spoofed := evalValue(t, ctx, interp, `Object.create(AbortSignal.prototype)`)
+	if spoofed.IsAbortSignal(ctx) {
+		t.Error("IsAbortSignal(Object.create(AbortSignal.prototype)) = true, want false")
+	}
+
+	// Abort a controller created by the script, with listeners
+	jsController := evalValue(t, ctx, interp, `
+		const g = globalThis as any;
+		g.log = [];
+		g.ctl = new AbortController();
+		g.ctl.signal.onabort = (e: any) => g.log.push("onabort:" + e.target.reason);
+		g.ctl.signal.addEventListener("abort", () => g.log.push("listener"));
+		g.ctl
+	`)
+	if err := interp.AbortWithReason(ctx, jsController, "stop"); err != nil {
+		t.Fatalf("AbortWithReason: %v", err)
+	}
+	if err := interp.AbortWithReason(ctx, jsController, "again"); err != nil {
+		t.Fatalf("AbortWithReason (second): %v", err)
+	}
+	got, _ := evalValue(t, ctx, interp, `
+		const h = globalThis as any;
+		JSON.stringify([h.ctl.signal.aborted, h.ctl.signal.reason, h.log])
+	`).AsString(ctx)
+	if want := `[true,"stop",["onabort:stop","listener"]]`; got != want {
+		t.Errorf("after AbortWithReason: %s, want %s", got, want)
+	}
+
+	if err := interp.AbortWithReason(ctx, lookalike, "stop"); err == nil {
+		t.Error("AbortWithReason(look-alike): expected error")
+	}
+
+	// The bindings do not go through the globals
+	evalValue(t, ctx, interp, `(globalThis as any).AbortController = class { signal = {} }; 0`)
+	fresh, err := interp.NewAbortController(ctx)
+	if err != nil {
+		t.Fatalf("NewAbortController after replacing global: %v", err)
+	}
+	defer fresh.Free(ctx)
+	freshSignal, err := fresh.Get(ctx, "signal")
+	if err != nil {
+		t.Fatalf("Get(signal): %v", err)
+	}
+	defer freshSignal.Free(ctx)
+	if !freshSignal.IsAbortSignal(ctx) {
+		t.Error("NewAbortController after replacing global did not return a real controller")
+	}
+}
+
+func TestAbortControllerNotEnabled(t *testing.T) {
+	ctx, interp := newTestContext(t)
+
+	if _, err := interp.NewAbortController(ctx); err == nil {
+		t.Error("NewAbortController: expected error without WithAbortControllerAPI")
+	}
+	v := evalValue(t, ctx, interp, `typeof AbortController`)
+	if got, _ := v.AsString(ctx); got != "undefined" {
+		t.Errorf("typeof AbortController = %q, want \"undefined\"", got)
+	}
+}
+
+func TestSetAbortController(t *testing.T) {
+	ctx, interp := newTestContext(t, WithAbortControllerAPI())
+
+	controller := evalValue(t, ctx, interp, `
+		const g = globalThis as any;
+		g.ctl = new AbortController();
+		g.ctl
+	`)
+	if err := interp.SetAbortController(controller); err != nil {
+		t.Fatalf("SetAbortController: %v", err)
+	}
+
+	// Not aborted while the context is live
+	got, _ := evalValue(t, ctx, interp, `String((globalThis as any).ctl.signal.aborted)`).AsString(ctx)
+	if got != "false" {
+		t.Fatalf("aborted before cancel = %s, want false", got)
+	}
+
+	cancelled, cancel := context.WithCancel(ctx)
+	cancel()
+	got, _ = evalValue(t, cancelled, interp, `String((globalThis as any).ctl.signal.reason)`).AsString(ctx)
+	if want := context.Canceled.Error(); got != want {
+		t.Errorf("reason after cancel = %q, want %q", got, want)
+	}
+}
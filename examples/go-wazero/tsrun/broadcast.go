@@ -0,0 +1,394 @@
+package tsrun
+
+import (
+	"context"
+	"fmt"
+	"strings"
+	"sync"
+)
+
+// broadcastChannelShim implements BroadcastChannel on top of the order
+// system. Each channel is a subscriber on the Go side; messages are
+// delivered by a background listen loop awaiting "receive" orders.
+const broadcastChannelShim = `
+import { order } from "tsrun:host";
+
+class BroadcastChannel {
+	readonly name: string;
+	onmessage: ((event: { data: any }) => void) | null = null;
+	private id: number;
+	private closed = false;
+
+	constructor(name: string) {
+		this.name = String(name);
+		this.id = order({ type: "tsrun:broadcast:subscribe", name: this.name });
+		this.listen();
+	}
+
+	private async listen() {
+		while (!this.closed) {
+			const message = await order({ type: "tsrun:broadcast:receive", id: this.id });
+			if (message === null) break;
+			if (this.onmessage) this.onmessage({ data: JSON.parse(message) });
+		}
+	}
+
+	postMessage(data: any): void {
+		if (this.closed) throw new Error("BroadcastChannel is closed");
+		// Receivers JSON.parse every message, so reject data that has no
+		// JSON form before it is sent
+		const type = typeof data;
+		if (type === "undefined" || type === "function" || type === "symbol") {
+			const error = new Error("BroadcastChannel cannot send " + type + " data");
+			error.name = "DataCloneError";
+			throw error;
+		}
+		order({ type: "tsrun:broadcast:post", id: this.id, data: JSON.stringify(data) });
+	}
+
+	close(): void {
+		if (this.closed) return;
+		this.closed = true;
+		order({ type: "tsrun:broadcast:close", id: this.id });
+	}
+}
+
+globalThis.BroadcastChannel = BroadcastChannel;
+`
+
+// broadcastChannelPath is the module path the shim is loaded from.
+const broadcastChannelPath = "/tsrun/broadcast-channel.ts"
+
+// broadcastOrderPrefix marks orders handled internally by the
+// BroadcastChannel API.
+const broadcastOrderPrefix = "tsrun:broadcast:"
+
+// broadcastChannels maps channel names to their hubs. It is shared by all
+// runtimes in the process so contexts in a worker pool can communicate.
+var broadcastChannels sync.Map // string -> *broadcastHub
+
+// broadcastHub holds the subscribers of one channel name. A hub is removed
+// from broadcastChannels when its last subscriber leaves.
+type broadcastHub struct {
+	mu          sync.Mutex
+	subscribers map[*broadcastSubscriber]struct{}
+	removed     bool // Set once the hub is no longer in broadcastChannels
+}
+
+// maxBroadcastQueue caps the messages queued for one subscriber, so a
+// context that is no longer run cannot grow memory without bound.
+const maxBroadcastQueue = 1024
+
+// broadcastSubscriber is the Go side of one JS BroadcastChannel object.
+type broadcastSubscriber struct {
+	name string
+
+	mu    sync.Mutex
+	queue []string // JSON-encoded messages awaiting delivery, oldest first
+}
+
+// WithBroadcastChannelAPI installs a BroadcastChannel global in every
+// context created by the runtime. Channels with the same name communicate
+// across all contexts and runtimes in the process; message data is copied
+// as JSON, and postMessage throws a DataCloneError for data with no JSON
+// form, such as undefined or a function.
+//
+// Messages are delivered when the receiving context runs, so a context with
+// an open channel stays suspended until the channel is closed. Each channel
+// queues at most 1024 undelivered messages, dropping the oldest beyond
+// that. Broadcast orders are handled inside Run and Step and never appear
+// in StepResult.PendingOrders.
+func WithBroadcastChannelAPI() func(*Runtime) {
+	return func(r *Runtime) {
+		r.broadcastChannelAPI = true
+	}
+}
+
+// installBroadcastChannel runs the BroadcastChannel shim in the context.
+//
+// The shim is imported from an anonymous script rather than prepared
+// directly: the first module prepared in a context becomes its main module,
+// whose exports tsrun_get_export reads, and that should be the user's.
+func (c *Context) installBroadcastChannel(ctx context.Context) error {
+	if err := c.Prepare(ctx, `import "`+broadcastChannelPath+`";`, ""); err != nil {
+		return err
+	}
+
+	for {
+		result, err := c.Run(ctx)
+		if err != nil {
+			return err
+		}
+
+		switch result.Status {
+		case StatusNeedImports:
+			for _, req := range result.ImportRequests {
+				if req.ResolvedPath != broadcastChannelPath {
+					return fmt.Errorf("broadcast channel setup: unexpected import %q", req.Specifier)
+				}
+				if err := c.ProvideModule(ctx, req.ResolvedPath, broadcastChannelShim); err != nil {
+					return err
+				}
+			}
+
+		case StatusError:
+			return fmt.Errorf("broadcast channel setup error: %s", result.Error)
+
+		case StatusSuspended:
+			return fmt.Errorf("broadcast channel setup suspended unexpectedly")
+
+		default:
+			if result.Value != nil {
+				result.Value.Free(ctx)
+			}
+			return nil
+		}
+	}
+}
+
+// deliverBroadcastMessages resolves waiting receive orders for which a
+// message has arrived.
+func (c *Context) deliverBroadcastMessages(ctx context.Context) error {
+	for id, promise := range c.broadcastWaiting {
+		sub, ok := c.broadcastSubs[id]
+		if !ok {
+			continue
+		}
+		message, ok := sub.pop()
+		if !ok {
+			continue
+		}
+
+		val, err := c.String(ctx, message)
+		if err != nil {
+			return err
+		}
+		err = c.ResolvePromise(ctx, promise, val)
+		val.Free(ctx)
+		if err != nil {
+			return err
+		}
+		delete(c.broadcastWaiting, id)
+		promise.Free(ctx)
+	}
+	return nil
+}
+
+// handleBroadcastOrders fulfills BroadcastChannel orders and removes them
+// from the result. Reports whether any order was handled.
+func (c *Context) handleBroadcastOrders(ctx context.Context, result *StepResult) (bool, error) {
+	var remaining []Order
+	var responses []OrderResponse
+	for _, order := range result.PendingOrders {
+		orderType := payloadString(ctx, order.Payload, "type")
+		if !strings.HasPrefix(orderType, broadcastOrderPrefix) {
+			remaining = append(remaining, order)
+			continue
+		}
+
+		resp, err := c.handleBroadcastOrder(ctx, order, strings.TrimPrefix(orderType, broadcastOrderPrefix))
+		if err != nil {
+			resp = OrderResponse{ID: order.ID, Error: err.Error()}
+		}
+		responses = append(responses, resp)
+	}
+
+	if len(responses) == 0 {
+		return false, nil
+	}
+
+	result.PendingOrders = remaining
+	err := c.FulfillOrders(ctx, responses)
+
+	// Keep promises awaiting delivery; free everything else
+	waiting := make(map[*Value]bool, len(c.broadcastWaiting))
+	for _, promise := range c.broadcastWaiting {
+		waiting[promise] = true
+	}
+	for _, resp := range responses {
+		if resp.Value != nil && !waiting[resp.Value] {
+			resp.Value.Free(ctx)
+		}
+	}
+	return true, err
+}
+
+// handleBroadcastOrder performs a single BroadcastChannel operation.
+func (c *Context) handleBroadcastOrder(ctx context.Context, order Order, op string) (OrderResponse, error) {
+	resp := OrderResponse{ID: order.ID}
+
+	if op == "subscribe" {
+		sub := subscribeBroadcast(payloadString(ctx, order.Payload, "name"))
+		c.nextBroadcastID++
+		c.broadcastSubs[c.nextBroadcastID] = sub
+
+		val, err := c.Number(ctx, float64(c.nextBroadcastID))
+		resp.Value = val
+		return resp, err
+	}
+
+	id := uint64(payloadNumber(ctx, order.Payload, "id"))
+	sub, ok := c.broadcastSubs[id]
+	if !ok {
+		return resp, fmt.Errorf("BroadcastChannel %d is closed", id)
+	}
+
+	switch op {
+	case "post":
+		sub.broadcast(payloadString(ctx, order.Payload, "data"))
+		return resp, nil
+
+	case "receive":
+		if message, ok := sub.pop(); ok {
+			val, err := c.String(ctx, message)
+			resp.Value = val
+			return resp, err
+		}
+		// Wait for a message: hand JS a promise resolved on delivery.
+		// It is internal, so CancelOrder and CancelAllOrders leave it alone.
+		promise, err := c.createOrderPromise(ctx, order.ID)
+		if err != nil {
+			return resp, err
+		}
+		c.broadcastWaiting[id] = promise
+		resp.Value = promise
+		return resp, nil
+
+	case "close":
+		return resp, c.closeBroadcastSubscriber(ctx, id)
+
+	default:
+		return resp, fmt.Errorf("unknown broadcast operation: %s", op)
+	}
+}
+
+// closeBroadcastSubscriber unsubscribes a channel and ends its listen loop.
+func (c *Context) closeBroadcastSubscriber(ctx context.Context, id uint64) error {
+	sub, ok := c.broadcastSubs[id]
+	if !ok {
+		return nil
+	}
+	sub.unsubscribe()
+	delete(c.broadcastSubs, id)
+
+	promise, ok := c.broadcastWaiting[id]
+	if !ok {
+		return nil
+	}
+	delete(c.broadcastWaiting, id)
+
+	defer promise.Free(ctx)
+
+	null, err := c.Null(ctx)
+	if err != nil {
+		return err
+	}
+	defer null.Free(ctx)
+	return c.ResolvePromise(ctx, promise, null)
+}
+
+// subscribeBroadcast adds a new subscriber to the named channel.
+func subscribeBroadcast(name string) *broadcastSubscriber {
+	sub := &broadcastSubscriber{name: name}
+	for {
+		hubVal, _ := broadcastChannels.LoadOrStore(name, &broadcastHub{
+			subscribers: make(map[*broadcastSubscriber]struct{}),
+		})
+		hub := hubVal.(*broadcastHub)
+
+		hub.mu.Lock()
+		if hub.removed {
+			// Lost a race with the last subscriber leaving; use a new hub
+			hub.mu.Unlock()
+			continue
+		}
+		hub.subscribers[sub] = struct{}{}
+		hub.mu.Unlock()
+		return sub
+	}
+}
+
+// broadcast queues a message for every other subscriber of the channel.
+func (s *broadcastSubscriber) broadcast(message string) {
+	hubVal, ok := broadcastChannels.Load(s.name)
+	if !ok {
+		return
+	}
+	hub := hubVal.(*broadcastHub)
+
+	hub.mu.Lock()
+	defer hub.mu.Unlock()
+	for other := range hub.subscribers {
+		if other == s {
+			continue
+		}
+		other.push(message)
+	}
+}
+
+// push queues a message, dropping the oldest if the queue is full.
+func (s *broadcastSubscriber) push(message string) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	if len(s.queue) >= maxBroadcastQueue {
+		s.queue = s.queue[1:]
+	}
+	s.queue = append(s.queue, message)
+}
+
+// pop removes the oldest queued message.
+func (s *broadcastSubscriber) pop() (string, bool) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	if len(s.queue) == 0 {
+		return "", false
+	}
+	message := s.queue[0]
+	s.queue = s.queue[1:]
+	return message, true
+}
+
+// unsubscribe removes the subscriber from its channel hub.
+func (s *broadcastSubscriber) unsubscribe() {
+	hubVal, ok := broadcastChannels.Load(s.name)
+	if !ok {
+		return
+	}
+	hub := hubVal.(*broadcastHub)
+
+	hub.mu.Lock()
+	defer hub.mu.Unlock()
+	delete(hub.subscribers, s)
+	if len(hub.subscribers) == 0 && !hub.removed {
+		hub.removed = true
+		broadcastChannels.CompareAndDelete(s.name, hub)
+	}
+}
+
+// payloadString reads a string property from an order payload.
+func payloadString(ctx context.Context, payload *Value, key string) string {
+	if payload == nil {
+		return ""
+	}
+	val, err := payload.Get(ctx, key)
+	if err != nil || val == nil {
+		return ""
+	}
+	defer val.Free(ctx)
+	s, _ := val.AsString(ctx)
+	return s
+}
+
+// payloadNumber reads a number property from an order payload.
+func payloadNumber(ctx context.Context, payload *Value, key string) float64 {
+	if payload == nil {
+		return 0
+	}
+	val, err := payload.Get(ctx, key)
+	if err != nil || val == nil {
+		return 0
+	}
+	defer val.Free(ctx)
+	n, _ := val.AsNumber(ctx)
+	return n
+}
@@ -0,0 +1,125 @@
+package tsrun
+
+import (
+	"strconv"
+	"testing"
+)
+
+func TestBroadcastHubRemovedWhenEmpty(t *testing.T) {
+	const name = "test-hub-removed"
+
+	first := subscribeBroadcast(name)
+	second := subscribeBroadcast(name)
+
+	first.unsubscribe()
+	if _, ok := broadcastChannels.Load(name); !ok {
+		t.Fatal("hub removed while it still has a subscriber")
+	}
+
+	second.unsubscribe()
+	if _, ok := broadcastChannels.Load(name); ok {
+		t.Fatal("empty hub not removed")
+	}
+
+	// A later subscriber gets a fresh hub
+	third := subscribeBroadcast(name)
+	defer third.unsubscribe()
+	hubVal, ok := broadcastChannels.Load(name)
+	if !ok {
+		t.Fatal("no hub after resubscribing")
+	}
+	if _, ok := hubVal.(*broadcastHub).subscribers[third]; !ok {
+		t.Fatal("subscriber missing from new hub")
+	}
+}
+
+func TestBroadcastQueueDropsOldest(t *testing.T) {
+	const name = "test-queue-cap"
+
+	sender := subscribeBroadcast(name)
+	defer sender.unsubscribe()
+	receiver := subscribeBroadcast(name)
+	defer receiver.unsubscribe()
+
+	for i := 0; i < maxBroadcastQueue+10; i++ {
+		sender.broadcast(strconv.Itoa(i))
+	}
+	if n := len(receiver.queue); n != maxBroadcastQueue {
+		t.Fatalf("queue length = %d, want %d", n, maxBroadcastQueue)
+	}
+	if message, _ := receiver.pop(); message != "10" {
+		t.Errorf("oldest message = %s, want 10", message)
+	}
+}
+
+func TestBroadcastBetweenContexts(t *testing.T) {
+	ctx, receiver := newTestContext(t, WithBroadcastChannelAPI())
+	sender, err := receiver.rt.NewContext(ctx)
+	if err != nil {
+		t.Fatalf("NewContext: %v", err)
+	}
+	defer sender.Free(ctx)
+
+	// The receiver waits for one message, then closes its channel
+	result := mustPrepareRun(t, ctx, receiver, `
+		const ch = new BroadcastChannel("test-e2e");
+		const data = await new Promise<any>((resolve) => {
+			ch.onmessage = (event) => {
+				ch.close();
+				resolve(event.data);
+			};
+		});
+		JSON.stringify(data)
+	`)
+	if result.Status != StatusSuspended || len(result.PendingOrders) != 0 {
+		t.Fatalf("receiver: status %s with %d orders, want Suspended with 0", result.Status, len(result.PendingOrders))
+	}
+
+	// Data without a JSON form is rejected before it reaches the receiver
+	got := completionString(t, ctx, mustPrepareRun(t, ctx, sender, `
+		const ch = new BroadcastChannel("test-e2e");
+		const errors: string[] = [];
+		for (const data of [undefined, () => {}, Symbol("s")]) {
+			try {
+				ch.postMessage(data);
+			} catch (e) {
+				errors.push(e.name);
+			}
+		}
+		ch.postMessage({ list: ["a", 1] });
+		ch.close();
+		errors.join(",")
+	`))
+	if want := "DataCloneError,DataCloneError,DataCloneError"; got != want {
+		t.Errorf("postMessage errors = %q, want %q", got, want)
+	}
+
+	got = completionString(t, ctx, mustRun(t, ctx, receiver))
+	if want := `{"list":["a",1]}`; got != want {
+		t.Errorf("received %s, want %s", got, want)
+	}
+}
+
+func TestBroadcastReceiveNotCancelled(t *testing.T) {
+	ctx, interp := newTestContext(t, WithBroadcastChannelAPI())
+
+	// The channel's listen loop waits on an internal receive promise while
+	// the script suspends on a user order
+	result := mustPrepareRun(t, ctx, interp, `
+		import { order } from "tsrun:host";
+		const ch = new BroadcastChannel("test-receive");
+		order({ type: "user" });
+		ch.close();
+	`)
+	singleOrder(t, result)
+
+	if len(interp.broadcastWaiting) != 1 {
+		t.Fatalf("%d receive promises waiting, want 1", len(interp.broadcastWaiting))
+	}
+	if err := interp.CancelAllOrders(ctx); err != nil {
+		t.Fatalf("CancelAllOrders: %v", err)
+	}
+	if len(interp.broadcastWaiting) != 1 {
+		t.Fatal("CancelAllOrders touched the internal receive promise")
+	}
+}
@@ -2,17 +2,47 @@ package tsrun
 
 import (
 	"context"
+	"errors"
 	"fmt"
 )
 
 // Context represents a tsrun interpreter context.
+// A Context is not safe for concurrent use.
 type Context struct {
 	rt     *Runtime
 	handle uint32 // Pointer to TsRunContext
+
+	// Order tracking for host-side cancellation
+	pendingOrders map[uint64]struct{} // Orders not yet fulfilled
+	orderPromises map[uint64]*Value   // Promises from CreateOrderPromise not yet settled
+
+	// BroadcastChannel state (see WithBroadcastChannelAPI)
+	broadcastSubs    map[uint64]*broadcastSubscriber
+	broadcastWaiting map[uint64]*Value // Receive promises by subscriber ID
+	nextBroadcastID  uint64
+
+	// AbortController state (see WithAbortControllerAPI)
+	abortHelpers    *Value // Private helpers returned by the shim
+	abortController *Value // Aborted when Run's context is done
+
+	evalModuleCount uint64 // Counter for EvalModule paths
 }
 
-// NewContext creates a new interpreter context.
+// ContextOption configures a Context created with NewContextWithOptions.
+// Options are applied in order after the underlying context is created and
+// may use any Context method, for example to run setup code. If an option
+// returns an error, the context is freed and NewContextWithOptions returns
+// that error.
+type ContextOption func(*Context) error
+
+// NewContext creates a new interpreter context with default settings.
+// It is equivalent to NewContextWithOptions with no options.
 func (r *Runtime) NewContext(ctx context.Context) (*Context, error) {
+	return r.NewContextWithOptions(ctx)
+}
+
+// NewContextWithOptions creates a new interpreter context configured by opts.
+func (r *Runtime) NewContextWithOptions(ctx context.Context, opts ...ContextOption) (*Context, error) {
 	results, err := r.fnNew.Call(ctx)
 	if err != nil {
 		return nil, fmt.Errorf("failed to create context: %w", err)
@@ -23,10 +53,39 @@ func (r *Runtime) NewContext(ctx context.Context) (*Context, error) {
 		return nil, fmt.Errorf("context creation returned null")
 	}
 
-	return &Context{
-		rt:     r,
-		handle: handle,
-	}, nil
+	c := &Context{
+		rt:               r,
+		handle:           handle,
+		pendingOrders:    make(map[uint64]struct{}),
+		orderPromises:    make(map[uint64]*Value),
+		broadcastSubs:    make(map[uint64]*broadcastSubscriber),
+		broadcastWaiting: make(map[uint64]*Value),
+	}
+
+	// Apply options
+	for _, opt := range opts {
+		if err := opt(c); err != nil {
+			c.Free(ctx)
+			return nil, fmt.Errorf("context option: %w", err)
+		}
+	}
+
+	// Install optional APIs after the options, so they run under any limits
+	// the options set
+	if r.broadcastChannelAPI {
+		if err := c.installBroadcastChannel(ctx); err != nil {
+			c.Free(ctx)
+			return nil, fmt.Errorf("failed to install BroadcastChannel: %w", err)
+		}
+	}
+	if r.abortControllerAPI {
+		if err := c.installAbortController(ctx); err != nil {
+			c.Free(ctx)
+			return nil, fmt.Errorf("failed to install AbortController: %w", err)
+		}
+	}
+
+	return c, nil
 }
 
 // Free releases the context resources.
@@ -34,6 +93,12 @@ func (c *Context) Free(ctx context.Context) error {
 	if c.handle == 0 {
 		return nil
 	}
+	for _, sub := range c.broadcastSubs {
+		sub.unsubscribe()
+	}
+	if c.abortHelpers != nil {
+		c.abortHelpers.Free(ctx)
+	}
 	_, err := c.rt.fnFree.Call(ctx, uint64(c.handle))
 	c.handle = 0
 	return err
@@ -88,8 +153,52 @@ func (c *Context) Prepare(ctx context.Context, code string, path string) error {
 	return nil
 }
 
-// Step executes one instruction.
+// EvalModule prepares and runs code as a module in one call, for REPL-like
+// evaluation. Module syntax and top-level await are supported. Each call
+// uses a fresh synthetic path ("/eval-module-1.ts", "/eval-module-2.ts", ...)
+// so relative imports resolve from the root.
+//
+// The result is handled like the result of Run: imports and orders still
+// need to be provided before the module completes.
+func (c *Context) EvalModule(ctx context.Context, code string) (*StepResult, error) {
+	c.evalModuleCount++
+	path := fmt.Sprintf("/eval-module-%d.ts", c.evalModuleCount)
+
+	if err := c.Prepare(ctx, code, path); err != nil {
+		return nil, err
+	}
+	return c.Run(ctx)
+}
+
+// Step executes one instruction. Like Run, it first aborts the controller set
+// with SetAbortController if ctx is done.
 func (c *Context) Step(ctx context.Context) (*StepResult, error) {
+	if err := c.abortIfDone(ctx); err != nil {
+		return nil, err
+	}
+
+	if !c.rt.broadcastChannelAPI {
+		return c.step(ctx)
+	}
+
+	if err := c.deliverBroadcastMessages(ctx); err != nil {
+		return nil, err
+	}
+
+	result, err := c.step(ctx)
+	if err != nil || result.Status != StatusSuspended {
+		return result, err
+	}
+
+	// Broadcast orders are fulfilled here; the next Step resumes execution
+	if _, err := c.handleBroadcastOrders(ctx, result); err != nil {
+		return nil, err
+	}
+	return result, nil
+}
+
+// step executes one fnStep call and parses its result.
+func (c *Context) step(ctx context.Context) (*StepResult, error) {
 	// Allocate space for TsRunStepResult struct (sret convention)
 	// TsRunStepResult layout (wasm32):
 	// - status: i32 (4 bytes)
@@ -117,8 +226,38 @@ func (c *Context) Step(ctx context.Context) (*StepResult, error) {
 	return c.parseStepResultFromPtr(ctx, resultPtr, resultSize)
 }
 
-// Run executes until completion, needing imports, or suspension.
+// Run executes until completion, needing imports, or suspension. If ctx is
+// done, the controller set with SetAbortController is aborted first.
 func (c *Context) Run(ctx context.Context) (*StepResult, error) {
+	if err := c.abortIfDone(ctx); err != nil {
+		return nil, err
+	}
+	if !c.rt.broadcastChannelAPI {
+		return c.run(ctx)
+	}
+
+	if err := c.deliverBroadcastMessages(ctx); err != nil {
+		return nil, err
+	}
+
+	for {
+		result, err := c.run(ctx)
+		if err != nil || result.Status != StatusSuspended {
+			return result, err
+		}
+
+		handled, err := c.handleBroadcastOrders(ctx, result)
+		if err != nil {
+			return nil, err
+		}
+		if !handled || len(result.PendingOrders) > 0 {
+			return result, nil
+		}
+	}
+}
+
+// run executes one fnRun call and parses its result.
+func (c *Context) run(ctx context.Context) (*StepResult, error) {
 	// Same struct size as Step
 	const resultSize = 36
 	resultPtr, err := c.rt.allocResult(ctx, resultSize)
@@ -180,6 +319,16 @@ func (c *Context) parseStepResultFromPtr(ctx context.Context, resultPtr uint32,
 	case StatusSuspended:
 		result.PendingOrders = c.parsePendingOrders(pendingPtr, pendingCount)
 		result.CancelledOrders = c.parseCancelledOrders(cancelledPtr, cancelledCount)
+		result.CancelledOrderIDs = result.CancelledOrders
+
+		// Track orders for CancelOrder/CancelAllOrders
+		for _, order := range result.PendingOrders {
+			c.pendingOrders[order.ID] = struct{}{}
+		}
+		for _, id := range result.CancelledOrders {
+			delete(c.pendingOrders, id)
+			delete(c.orderPromises, id)
+		}
 	}
 
 	// Free the step result structure's internal arrays (but not the value)
@@ -400,6 +549,10 @@ func (c *Context) FulfillOrders(ctx context.Context, responses []OrderResponse)
 		return fmt.Errorf("fulfill_orders error: %s", c.rt.readString(errorPtr))
 	}
 
+	for _, resp := range responses {
+		delete(c.pendingOrders, resp.ID)
+	}
+
 	return nil
 }
 
@@ -407,6 +560,17 @@ func (c *Context) FulfillOrders(ctx context.Context, responses []OrderResponse)
 // The returned promise can be used as the order response value, and then
 // resolved later using ResolvePromise.
 func (c *Context) CreateOrderPromise(ctx context.Context, orderID uint64) (*Value, error) {
+	promise, err := c.createOrderPromise(ctx, orderID)
+	if err != nil {
+		return nil, err
+	}
+	c.orderPromises[orderID] = promise
+	return promise, nil
+}
+
+// createOrderPromise creates an order promise without tracking it for
+// CancelOrder, for promises the bindings settle themselves.
+func (c *Context) createOrderPromise(ctx context.Context, orderID uint64) (*Value, error) {
 	if c.rt.fnCreateOrderPromise == nil {
 		return nil, fmt.Errorf("create_order_promise not available")
 	}
@@ -472,6 +636,7 @@ func (c *Context) ResolvePromise(ctx context.Context, promise *Value, value *Val
 		return fmt.Errorf("resolve_promise error: %s", c.rt.readString(errorPtr))
 	}
 
+	c.forgetOrderPromise(promise)
 	return nil
 }
 
@@ -510,5 +675,260 @@ func (c *Context) RejectPromise(ctx context.Context, promise *Value, errorMsg st
 		return fmt.Errorf("reject_promise error: %s", c.rt.readString(errMsgPtr))
 	}
 
+	c.forgetOrderPromise(promise)
 	return nil
 }
+
+// forgetOrderPromise stops tracking a settled order promise.
+func (c *Context) forgetOrderPromise(promise *Value) {
+	for id, p := range c.orderPromises {
+		if p.handle == promise.handle {
+			delete(c.orderPromises, id)
+			return
+		}
+	}
+}
+
+// CancelOrder cancels a pending order from the host side.
+// The awaiting TypeScript code receives an Error whose name is
+// "CancelError", so scripts can tell cancellation apart from other failures:
+//
+//	try {
+//		await order({ type: "fetch", url });
+//	} catch (e) {
+//		if (e.name === "CancelError") return;
+//		throw e;
+//	}
+//
+// Works both for orders that have not been fulfilled yet and for orders
+// fulfilled with a promise from CreateOrderPromise.
+//
+// Like other Context methods, CancelOrder is not safe for concurrent use and
+// must be called from the goroutine that calls Run. To cancel when e.g. an
+// HTTP client disconnects, have the run loop watch the request's context and
+// cancel from there.
+func (c *Context) CancelOrder(ctx context.Context, orderID uint64) error {
+	if _, ok := c.pendingOrders[orderID]; ok {
+		// Any promise created for the order is never handed to JS now
+		delete(c.orderPromises, orderID)
+		return c.cancelPendingOrder(ctx, orderID)
+	}
+
+	if promise, ok := c.orderPromises[orderID]; ok {
+		return c.cancelOrderPromise(ctx, orderID, promise)
+	}
+
+	return fmt.Errorf("order %d is not pending", orderID)
+}
+
+// CancelAllOrders cancels every pending order, e.g. when the request that
+// started the execution has gone away. See CancelOrder.
+//
+// A failure to cancel one order does not stop the others from being
+// cancelled; all failures are returned joined.
+func (c *Context) CancelAllOrders(ctx context.Context) error {
+	var errs []error
+	for id := range c.pendingOrders {
+		delete(c.orderPromises, id)
+		if err := c.cancelPendingOrder(ctx, id); err != nil {
+			errs = append(errs, err)
+		}
+	}
+
+	for id, promise := range c.orderPromises {
+		if err := c.cancelOrderPromise(ctx, id, promise); err != nil {
+			errs = append(errs, err)
+		}
+	}
+
+	return errors.Join(errs...)
+}
+
+// cancelPendingOrder throws a CancelError at the order() call.
+func (c *Context) cancelPendingOrder(ctx context.Context, orderID uint64) error {
+	if c.rt.fnRejectOrder == nil {
+		return fmt.Errorf("reject_order not available")
+	}
+
+	reason, err := c.cancelError(ctx, orderID)
+	if err != nil {
+		return err
+	}
+	defer reason.Free(ctx)
+
+	// TsRunResult: { ok: bool (4 bytes), error: *const c_char (4 bytes) } = 8 bytes
+	const resultSize = 8
+	resultPtr, err := c.rt.allocResult(ctx, resultSize)
+	if err != nil {
+		return fmt.Errorf("failed to allocate result: %w", err)
+	}
+	defer c.rt.deallocResult(ctx, resultPtr, resultSize)
+
+	// Call tsrun_reject_order(sret, ctx, order_id, reason)
+	_, err = c.rt.fnRejectOrder.Call(ctx, uint64(resultPtr), uint64(c.handle), orderID, uint64(reason.handle))
+	if err != nil {
+		return fmt.Errorf("reject_order call failed: %w", err)
+	}
+
+	okVal, _ := c.rt.memory.ReadUint32Le(resultPtr)
+	errorPtr, _ := c.rt.memory.ReadUint32Le(resultPtr + 4)
+
+	if okVal == 0 {
+		return fmt.Errorf("reject_order error: %s", c.rt.readString(errorPtr))
+	}
+
+	delete(c.pendingOrders, orderID)
+	return nil
+}
+
+// cancelOrderPromise rejects an order's promise with a CancelError.
+// The promise is no longer tracked afterwards, even if rejecting it failed.
+func (c *Context) cancelOrderPromise(ctx context.Context, orderID uint64, promise *Value) error {
+	delete(c.orderPromises, orderID)
+
+	if promise.handle == 0 {
+		return fmt.Errorf("order %d: promise was freed before it was settled", orderID)
+	}
+	if c.rt.fnRejectPromiseValue == nil {
+		return fmt.Errorf("reject_promise_value not available")
+	}
+
+	reason, err := c.cancelError(ctx, orderID)
+	if err != nil {
+		return err
+	}
+	defer reason.Free(ctx)
+
+	const resultSize = 8
+	resultPtr, err := c.rt.allocResult(ctx, resultSize)
+	if err != nil {
+		return fmt.Errorf("failed to allocate result: %w", err)
+	}
+	defer c.rt.deallocResult(ctx, resultPtr, resultSize)
+
+	// Call tsrun_reject_promise_value(sret, ctx, promise, reason)
+	_, err = c.rt.fnRejectPromiseValue.Call(ctx, uint64(resultPtr), uint64(c.handle), uint64(promise.handle), uint64(reason.handle))
+	if err != nil {
+		return fmt.Errorf("reject_promise_value call failed: %w", err)
+	}
+
+	okVal, _ := c.rt.memory.ReadUint32Le(resultPtr)
+	errorPtr, _ := c.rt.memory.ReadUint32Le(resultPtr + 4)
+
+	if okVal == 0 {
+		return fmt.Errorf("order %d: reject_promise_value error: %s", orderID, c.rt.readString(errorPtr))
+	}
+
+	return nil
+}
+
+// cancelError creates the CancelError passed to JS for a cancelled order.
+func (c *Context) cancelError(ctx context.Context, orderID uint64) (*Value, error) {
+	return c.newError(ctx, "CancelError", fmt.Sprintf("order %d cancelled by host", orderID))
+}
+
+// newError creates an Error object with the given name and message.
+// The caller must free the returned value.
+func (c *Context) newError(ctx context.Context, name string, message string) (*Value, error) {
+	if c.rt.fnErrorNew == nil {
+		return nil, fmt.Errorf("error_new not available")
+	}
+
+	namePtr, err := c.rt.allocString(ctx, name)
+	if err != nil {
+		return nil, err
+	}
+	defer c.rt.deallocString(ctx, namePtr, uint32(len(name)+1))
+
+	messagePtr, err := c.rt.allocString(ctx, message)
+	if err != nil {
+		return nil, err
+	}
+	defer c.rt.deallocString(ctx, messagePtr, uint32(len(message)+1))
+
+	// TsRunValueResult: { value: *TsRunValue (4 bytes), error: *c_char (4 bytes) } = 8 bytes
+	const resultSize = 8
+	resultPtr, err := c.rt.allocResult(ctx, resultSize)
+	if err != nil {
+		return nil, fmt.Errorf("failed to allocate result: %w", err)
+	}
+	defer c.rt.deallocResult(ctx, resultPtr, resultSize)
+
+	// Call with sret convention: (sret, ctx, name, message)
+	_, err = c.rt.fnErrorNew.Call(ctx, uint64(resultPtr), uint64(c.handle), uint64(namePtr), uint64(messagePtr))
+	if err != nil {
+		return nil, fmt.Errorf("error_new call failed: %w", err)
+	}
+
+	valuePtr, _ := c.rt.memory.ReadUint32Le(resultPtr)
+	errorPtr, _ := c.rt.memory.ReadUint32Le(resultPtr + 4)
+
+	if valuePtr == 0 {
+		return nil, fmt.Errorf("error_new error: %s", c.rt.readString(errorPtr))
+	}
+
+	return &Value{ctx: c, handle: valuePtr}, nil
+}
+
+// callMethod calls obj[method](...args) and returns the result.
+// The caller must free the returned value.
+func (c *Context) callMethod(ctx context.Context, obj *Value, method string, args ...*Value) (*Value, error) {
+	if c.rt.fnCallMethod == nil {
+		return nil, fmt.Errorf("call_method not available")
+	}
+
+	methodPtr, err := c.rt.allocString(ctx, method)
+	if err != nil {
+		return nil, err
+	}
+	defer c.rt.deallocString(ctx, methodPtr, uint32(len(method)+1))
+
+	// Array of *mut TsRunValue (4 bytes each on wasm32)
+	var argsPtr uint32
+	if len(args) > 0 {
+		argsSize := uint32(len(args) * 4)
+		argsPtr, err = c.rt.allocResult(ctx, argsSize)
+		if err != nil {
+			return nil, fmt.Errorf("failed to allocate args array: %w", err)
+		}
+		defer c.rt.deallocResult(ctx, argsPtr, argsSize)
+
+		for i, arg := range args {
+			var argHandle uint32
+			if arg != nil {
+				argHandle = arg.handle
+			}
+			c.rt.memory.WriteUint32Le(argsPtr+uint32(i*4), argHandle)
+		}
+	}
+
+	// TsRunValueResult: { value: *TsRunValue (4 bytes), error: *c_char (4 bytes) } = 8 bytes
+	const resultSize = 8
+	resultPtr, err := c.rt.allocResult(ctx, resultSize)
+	if err != nil {
+		return nil, fmt.Errorf("failed to allocate result: %w", err)
+	}
+	defer c.rt.deallocResult(ctx, resultPtr, resultSize)
+
+	// Call with sret convention: (sret, ctx, obj, method, args, argc)
+	_, err = c.rt.fnCallMethod.Call(ctx,
+		uint64(resultPtr),
+		uint64(c.handle),
+		uint64(obj.handle),
+		uint64(methodPtr),
+		uint64(argsPtr),
+		uint64(len(args)))
+	if err != nil {
+		return nil, fmt.Errorf("call_method call failed: %w", err)
+	}
+
+	// Read TsRunValueResult from memory
+	valuePtr, _ := c.rt.memory.ReadUint32Le(resultPtr)
+	errorPtr, _ := c.rt.memory.ReadUint32Le(resultPtr + 4)
+
+	if valuePtr == 0 {
+		return nil, fmt.Errorf("%s error: %s", method, c.rt.readString(errorPtr))
+	}
+
+	return &Value{ctx: c, handle: valuePtr}, nil
+}
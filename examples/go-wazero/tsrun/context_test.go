@@ -0,0 +1,217 @@
+package tsrun
+
+import (
+	"context"
+	"fmt"
+	"testing"
+)
+
+// mustPrepareRun prepares code and runs it, failing the test on error.
+func mustPrepareRun(tb testing.TB, ctx context.Context, interp *Context, code string) *StepResult {
+	tb.Helper()
+	if err := interp.Prepare(ctx, code, "/test.ts"); err != nil {
+		tb.Fatalf("Prepare: %v", err)
+	}
+	return mustRun(tb, ctx, interp)
+}
+
+// mustRun runs the context, failing the test on error.
+func mustRun(tb testing.TB, ctx context.Context, interp *Context) *StepResult {
+	tb.Helper()
+	result, err := interp.Run(ctx)
+	if err != nil {
+		tb.Fatalf("Run: %v", err)
+	}
+	if result.Status == StatusError {
+		tb.Fatalf("Run: %s", result.Error)
+	}
+	return result
+}
+
+// completionString returns the string completion value of a result.
+func completionString(tb testing.TB, ctx context.Context, result *StepResult) string {
+	tb.Helper()
+	if result.Status != StatusComplete || result.Value == nil {
+		tb.Fatalf("status %s, want Complete", result.Status)
+	}
+	defer result.Value.Free(ctx)
+	s, err := result.Value.AsString(ctx)
+	if err != nil {
+		tb.Fatalf("AsString: %v", err)
+	}
+	return s
+}
+
+// singleOrder returns the only pending order of a suspended result.
+func singleOrder(tb testing.TB, result *StepResult) uint64 {
+	tb.Helper()
+	if result.Status != StatusSuspended || len(result.PendingOrders) != 1 {
+		tb.Fatalf("status %s with %d orders, want Suspended with 1", result.Status, len(result.PendingOrders))
+	}
+	return result.PendingOrders[0].ID
+}
+
+func TestNewContextWithOptions(t *testing.T) {
+	ctx, interp := newTestContext(t)
+	rt := interp.rt
+
+	var order []string
+	setup := func(name string) ContextOption {
+		return func(c *Context) error {
+			order = append(order, name)
+			result := mustPrepareRun(t, ctx, c, `(globalThis as any).`+name+` = true; 0`)
+			return result.Value.Free(ctx)
+		}
+	}
+
+	c, err := rt.NewContextWithOptions(ctx, setup("first"), setup("second"))
+	if err != nil {
+		t.Fatalf("NewContextWithOptions: %v", err)
+	}
+	defer c.Free(ctx)
+	if got := fmt.Sprint(order); got != "[first second]" {
+		t.Errorf("options applied as %s, want [first second]", got)
+	}
+	got, _ := evalValue(t, ctx, c, `String((globalThis as any).first && (globalThis as any).second)`).AsString(ctx)
+	if got != "true" {
+		t.Errorf("globals set by options = %s, want true", got)
+	}
+
+	// A failing option stops creation; later options do not run
+	order = nil
+	fail := func(*Context) error { return fmt.Errorf("setup failed") }
+	if c, err := rt.NewContextWithOptions(ctx, fail, setup("never")); err == nil {
+		c.Free(ctx)
+		t.Error("NewContextWithOptions with a failing option: expected error")
+	}
+	if len(order) != 0 {
+		t.Errorf("options after the failure ran: %v", order)
+	}
+}
+
+func TestEvalModule(t *testing.T) {
+	ctx, interp := newTestContext(t)
+
+	tests := []struct {
+		code string
+		want float64
+	}{
+		{`const x = await Promise.resolve(40); x + 2`, 42},
+		{`export const y = 1; y + 1`, 2},
+		// Each evaluation is a separate module, so names can be reused
+		{`const x = await new Promise<number>((resolve) => resolve(7)); x`, 7},
+	}
+	for _, tt := range tests {
+		result, err := interp.EvalModule(ctx, tt.code)
+		if err != nil {
+			t.Fatalf("EvalModule(%q): %v", tt.code, err)
+		}
+		if result.Status != StatusComplete || result.Value == nil {
+			t.Fatalf("EvalModule(%q): status %s, error %q", tt.code, result.Status, result.Error)
+		}
+		got, err := result.Value.AsNumber(ctx)
+		result.Value.Free(ctx)
+		if err != nil {
+			t.Fatalf("EvalModule(%q): %v", tt.code, err)
+		}
+		if got != tt.want {
+			t.Errorf("EvalModule(%q) = %v, want %v", tt.code, got, tt.want)
+		}
+	}
+}
+
+const cancelScript = `
+	import { order } from "tsrun:host";
+	let caught: any;
+	try {
+		await order({ type: "slow" });
+	} catch (e) {
+		caught = e;
+	}
+	JSON.stringify([caught instanceof Error, caught.name, caught.message])
+`
+
+func TestCancelOrder(t *testing.T) {
+	ctx, interp := newTestContext(t)
+
+	id := singleOrder(t, mustPrepareRun(t, ctx, interp, cancelScript))
+	if err := interp.CancelOrder(ctx, id); err != nil {
+		t.Fatalf("CancelOrder: %v", err)
+	}
+
+	got := completionString(t, ctx, mustRun(t, ctx, interp))
+	want := fmt.Sprintf(`[true,"CancelError","order %d cancelled by host"]`, id)
+	if got != want {
+		t.Errorf("caught %s, want %s", got, want)
+	}
+
+	if err := interp.CancelOrder(ctx, id); err == nil {
+		t.Error("CancelOrder of a settled order: expected error")
+	}
+}
+
+func TestCancelOrderPromise(t *testing.T) {
+	ctx, interp := newTestContext(t)
+
+	id := singleOrder(t, mustPrepareRun(t, ctx, interp, cancelScript))
+	promise, err := interp.CreateOrderPromise(ctx, id)
+	if err != nil {
+		t.Fatalf("CreateOrderPromise: %v", err)
+	}
+	defer promise.Free(ctx)
+	if err := interp.FulfillOrders(ctx, []OrderResponse{{ID: id, Value: promise}}); err != nil {
+		t.Fatalf("FulfillOrders: %v", err)
+	}
+	if result := mustRun(t, ctx, interp); result.Status != StatusSuspended {
+		t.Fatalf("status %s, want Suspended", result.Status)
+	}
+
+	if err := interp.CancelOrder(ctx, id); err != nil {
+		t.Fatalf("CancelOrder: %v", err)
+	}
+
+	got := completionString(t, ctx, mustRun(t, ctx, interp))
+	want := fmt.Sprintf(`[true,"CancelError","order %d cancelled by host"]`, id)
+	if got != want {
+		t.Errorf("caught %s, want %s", got, want)
+	}
+}
+
+func TestCancelAllOrdersContinuesAfterError(t *testing.T) {
+	ctx, interp := newTestContext(t)
+
+	// The first order gets a promise that the host frees without settling;
+	// the second is still pending when everything is cancelled
+	result := mustPrepareRun(t, ctx, interp, `
+		import { order } from "tsrun:host";
+		const first = order({ n: 1 });
+		let caught: any;
+		try {
+			order({ n: 2 });
+		} catch (e) {
+			caught = e;
+		}
+		caught.name
+	`)
+	first := singleOrder(t, result)
+	promise, err := interp.CreateOrderPromise(ctx, first)
+	if err != nil {
+		t.Fatalf("CreateOrderPromise: %v", err)
+	}
+	if err := interp.FulfillOrders(ctx, []OrderResponse{{ID: first, Value: promise}}); err != nil {
+		t.Fatalf("FulfillOrders: %v", err)
+	}
+	singleOrder(t, mustRun(t, ctx, interp))
+	promise.Free(ctx)
+
+	if err := interp.CancelAllOrders(ctx); err == nil {
+		t.Error("CancelAllOrders with a freed promise: expected error")
+	}
+	if len(interp.pendingOrders) != 0 || len(interp.orderPromises) != 0 {
+		t.Errorf("still tracking %d orders and %d promises", len(interp.pendingOrders), len(interp.orderPromises))
+	}
+
+	if got := completionString(t, ctx, mustRun(t, ctx, interp)); got != "CancelError" {
+		t.Errorf("caught %s, want CancelError", got)
+	}
+}
@@ -0,0 +1,222 @@
+package tsrun
+
+import (
+	"context"
+	"fmt"
+	"reflect"
+)
+
+// maxConvertDepth limits nesting in ToGoValue so cyclic structures fail
+// instead of recursing forever.
+const maxConvertDepth = 64
+
+// ToGoValue converts the value to a Go value:
+//
+//	undefined, null  -> nil
+//	boolean          -> bool
+//	number           -> float64
+//	string           -> string
+//	Array, Set       -> []interface{}
+//	Map              -> map[interface{}]interface{} (see MapToGoMap)
+//	other objects    -> map[string]interface{} of the properties listed by Keys
+//
+// Returns an error for symbols, functions, Maps with object or array keys,
+// and structures nested deeper than 64 levels (including cycles).
+func (v *Value) ToGoValue(ctx context.Context) (interface{}, error) {
+	return v.toGoValue(ctx, 0)
+}
+
+// MapToGoMap converts a Map to a Go map, converting keys and values with
+// ToGoValue.
+//
+// Object and array keys convert to Go maps and slices, which cannot be Go
+// map keys, so such Maps return an error. Use MapEntries for Maps that may
+// have them.
+func (v *Value) MapToGoMap(ctx context.Context) (map[interface{}]interface{}, error) {
+	return v.mapToGoMap(ctx, 0)
+}
+
+// MapEntries converts a Map to a slice of [key, value] pairs in insertion
+// order, converting keys and values with ToGoValue. Unlike MapToGoMap, it
+// accepts keys of any type.
+func (v *Value) MapEntries(ctx context.Context) ([][2]interface{}, error) {
+	return v.mapEntries(ctx, 0)
+}
+
+// SetToGoSlice converts a Set to a slice in insertion order, converting
+// elements with ToGoValue.
+func (v *Value) SetToGoSlice(ctx context.Context) ([]interface{}, error) {
+	if !v.IsSet(ctx) {
+		return nil, fmt.Errorf("value is not a Set")
+	}
+	return v.setToGo(ctx, 0)
+}
+
+func (v *Value) toGoValue(ctx context.Context, depth int) (interface{}, error) {
+	if depth > maxConvertDepth {
+		return nil, fmt.Errorf("value nested too deeply (cyclic?)")
+	}
+
+	typ, err := v.Type(ctx)
+	if err != nil {
+		return nil, err
+	}
+
+	switch typ {
+	case TypeUndefined, TypeNull:
+		return nil, nil
+	case TypeBoolean:
+		return v.AsBool(ctx)
+	case TypeNumber:
+		return v.AsNumber(ctx)
+	case TypeString:
+		return v.AsString(ctx)
+	case TypeObject:
+		switch {
+		case v.IsFunction(ctx):
+			return nil, fmt.Errorf("cannot convert function to Go value")
+		case v.IsArray(ctx):
+			return v.arrayToGo(ctx, depth)
+		case v.IsSet(ctx):
+			return v.setToGo(ctx, depth)
+		case v.IsMap(ctx):
+			return v.mapToGoMap(ctx, depth)
+		default:
+			return v.objectToGo(ctx, depth)
+		}
+	default:
+		return nil, fmt.Errorf("cannot convert %s to Go value", typ)
+	}
+}
+
+// arrayToGo converts the elements of an array.
+func (v *Value) arrayToGo(ctx context.Context, depth int) ([]interface{}, error) {
+	length, err := v.ArrayLength(ctx)
+	if err != nil {
+		return nil, err
+	}
+
+	out := make([]interface{}, 0, length)
+	for i := uint32(0); i < length; i++ {
+		elem, err := v.ArrayGet(ctx, i)
+		if err != nil {
+			return nil, err
+		}
+		goElem, err := elem.toGoValue(ctx, depth+1)
+		elem.Free(ctx)
+		if err != nil {
+			return nil, fmt.Errorf("[%d]: %w", i, err)
+		}
+		out = append(out, goElem)
+	}
+	return out, nil
+}
+
+// collectionEntries returns a Map's [key, value] pairs or a Set's values as
+// a new array. The caller must free the returned value.
+func (v *Value) collectionEntries(ctx context.Context) (*Value, error) {
+	if v.handle == 0 || v.ctx.rt.fnCollectionEntries == nil {
+		return nil, fmt.Errorf("value is nil or function not available")
+	}
+
+	// TsRunValueResult: { value: *TsRunValue (4 bytes), error: *c_char (4 bytes) } = 8 bytes
+	const resultSize = 8
+	resultPtr, err := v.ctx.rt.allocResult(ctx, resultSize)
+	if err != nil {
+		return nil, fmt.Errorf("failed to allocate result: %w", err)
+	}
+	defer v.ctx.rt.deallocResult(ctx, resultPtr, resultSize)
+
+	// Call with sret convention: (sret, ctx, collection)
+	_, err = v.ctx.rt.fnCollectionEntries.Call(ctx, uint64(resultPtr), uint64(v.ctx.handle), uint64(v.handle))
+	if err != nil {
+		return nil, err
+	}
+
+	valuePtr, _ := v.ctx.rt.memory.ReadUint32Le(resultPtr)
+	errorPtr, _ := v.ctx.rt.memory.ReadUint32Le(resultPtr + 4)
+
+	if valuePtr == 0 {
+		return nil, fmt.Errorf("collection_entries error: %s", v.ctx.rt.readString(errorPtr))
+	}
+
+	return &Value{ctx: v.ctx, handle: valuePtr}, nil
+}
+
+// setToGo converts the values of a Set.
+func (v *Value) setToGo(ctx context.Context, depth int) ([]interface{}, error) {
+	arr, err := v.collectionEntries(ctx)
+	if err != nil {
+		return nil, err
+	}
+	defer arr.Free(ctx)
+
+	return arr.arrayToGo(ctx, depth)
+}
+
+func (v *Value) mapToGoMap(ctx context.Context, depth int) (map[interface{}]interface{}, error) {
+	entries, err := v.mapEntries(ctx, depth)
+	if err != nil {
+		return nil, err
+	}
+
+	out := make(map[interface{}]interface{}, len(entries))
+	for _, entry := range entries {
+		// Go maps and slices from object keys would panic as map keys
+		if entry[0] != nil && !reflect.TypeOf(entry[0]).Comparable() {
+			return nil, fmt.Errorf("cannot use %T Map key as Go map key (use MapEntries)", entry[0])
+		}
+		out[entry[0]] = entry[1]
+	}
+	return out, nil
+}
+
+func (v *Value) mapEntries(ctx context.Context, depth int) ([][2]interface{}, error) {
+	if !v.IsMap(ctx) {
+		return nil, fmt.Errorf("value is not a Map")
+	}
+
+	arr, err := v.collectionEntries(ctx)
+	if err != nil {
+		return nil, err
+	}
+	defer arr.Free(ctx)
+
+	items, err := arr.arrayToGo(ctx, depth)
+	if err != nil {
+		return nil, err
+	}
+
+	entries := make([][2]interface{}, 0, len(items))
+	for i, item := range items {
+		pair, ok := item.([]interface{})
+		if !ok || len(pair) != 2 {
+			return nil, fmt.Errorf("[%d]: Map entry is not a [key, value] pair", i)
+		}
+		entries = append(entries, [2]interface{}{pair[0], pair[1]})
+	}
+	return entries, nil
+}
+
+// objectToGo converts the properties of a plain object listed by Keys.
+func (v *Value) objectToGo(ctx context.Context, depth int) (map[string]interface{}, error) {
+	keys, err := v.Keys(ctx)
+	if err != nil {
+		return nil, err
+	}
+
+	out := make(map[string]interface{}, len(keys))
+	for _, key := range keys {
+		prop, err := v.Get(ctx, key)
+		if err != nil {
+			return nil, err
+		}
+		goProp, err := prop.toGoValue(ctx, depth+1)
+		prop.Free(ctx)
+		if err != nil {
+			return nil, fmt.Errorf("%s: %w", key, err)
+		}
+		out[key] = goProp
+	}
+	return out, nil
+}
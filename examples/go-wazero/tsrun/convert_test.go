@@ -0,0 +1,135 @@
+package tsrun
+
+import (
+	"reflect"
+	"testing"
+)
+
+func TestToGoValue(t *testing.T) {
+	ctx, interp := newTestContext(t)
+
+	v := evalValue(t, ctx, interp, `({ name: "a", n: 1, ok: true, none: null, list: [1, "x"] })`)
+	got, err := v.ToGoValue(ctx)
+	if err != nil {
+		t.Fatalf("ToGoValue: %v", err)
+	}
+	want := map[string]interface{}{
+		"name": "a",
+		"n":    float64(1),
+		"ok":   true,
+		"none": nil,
+		"list": []interface{}{float64(1), "x"},
+	}
+	if !reflect.DeepEqual(got, want) {
+		t.Errorf("ToGoValue = %#v, want %#v", got, want)
+	}
+
+	// Numeric keys are stored as index keys but read back by name
+	numeric := evalValue(t, ctx, interp, `({ "1": "a", 20: "b", "01": "c" })`)
+	got, err = numeric.ToGoValue(ctx)
+	if err != nil {
+		t.Fatalf("ToGoValue(numeric keys): %v", err)
+	}
+	want = map[string]interface{}{"1": "a", "20": "b", "01": "c"}
+	if !reflect.DeepEqual(got, want) {
+		t.Errorf("ToGoValue(numeric keys) = %#v, want %#v", got, want)
+	}
+
+	cyclic := evalValue(t, ctx, interp, `const o: any = {}; o.self = o; o`)
+	if _, err := cyclic.ToGoValue(ctx); err == nil {
+		t.Error("ToGoValue(cyclic): expected error")
+	}
+
+	// A nested Map with an object key cannot become a Go map
+	objectKey := evalValue(t, ctx, interp, `({ m: new Map([[{}, 1]]) })`)
+	if _, err := objectKey.ToGoValue(ctx); err == nil {
+		t.Error("ToGoValue(Map with object key): expected error")
+	}
+}
+
+func TestMapToGoMap(t *testing.T) {
+	ctx, interp := newTestContext(t)
+
+	v := evalValue(t, ctx, interp, `new Map<any, any>([["a", 1], [2, [true]]])`)
+	got, err := v.MapToGoMap(ctx)
+	if err != nil {
+		t.Fatalf("MapToGoMap: %v", err)
+	}
+	want := map[interface{}]interface{}{
+		"a":        float64(1),
+		float64(2): []interface{}{true},
+	}
+	if !reflect.DeepEqual(got, want) {
+		t.Errorf("MapToGoMap = %#v, want %#v", got, want)
+	}
+
+	set := evalValue(t, ctx, interp, `new Set([1])`)
+	if _, err := set.MapToGoMap(ctx); err == nil {
+		t.Error("MapToGoMap(Set): expected error")
+	}
+}
+
+func TestMapEntries(t *testing.T) {
+	ctx, interp := newTestContext(t)
+
+	// Object keys are not comparable in Go, so only MapEntries can hold them
+	v := evalValue(t, ctx, interp, `new Map<any, any>([[{ id: 1 }, "x"], ["b", 2]])`)
+	got, err := v.MapEntries(ctx)
+	if err != nil {
+		t.Fatalf("MapEntries: %v", err)
+	}
+	want := [][2]interface{}{
+		{map[string]interface{}{"id": float64(1)}, "x"},
+		{"b", float64(2)},
+	}
+	if !reflect.DeepEqual(got, want) {
+		t.Errorf("MapEntries = %#v, want %#v", got, want)
+	}
+
+	// Entries are read directly, not through a script's iterator
+	spoofed := evalValue(t, ctx, interp, `
+		const m = new Map<any, any>([["k", "v"]]);
+		(m as any)[Symbol.iterator] = function* () { yield "not a pair"; };
+		Map.prototype[Symbol.iterator] = function* () { yield 1; };
+		m
+	`)
+	got, err = spoofed.MapEntries(ctx)
+	if err != nil {
+		t.Fatalf("MapEntries(spoofed iterator): %v", err)
+	}
+	if want := [][2]interface{}{{"k", "v"}}; !reflect.DeepEqual(got, want) {
+		t.Errorf("MapEntries(spoofed iterator) = %#v, want %#v", got, want)
+	}
+}
+
+func TestSetToGoSlice(t *testing.T) {
+	ctx, interp := newTestContext(t)
+
+	v := evalValue(t, ctx, interp, `new Set<any>([3, "a", 3, null])`)
+	got, err := v.SetToGoSlice(ctx)
+	if err != nil {
+		t.Fatalf("SetToGoSlice: %v", err)
+	}
+	want := []interface{}{float64(3), "a", nil}
+	if !reflect.DeepEqual(got, want) {
+		t.Errorf("SetToGoSlice = %#v, want %#v", got, want)
+	}
+
+	spoofed := evalValue(t, ctx, interp, `
+		const s = new Set([1, 2]);
+		(s as any)[Symbol.iterator] = function* () { yield "spoofed"; };
+		s
+	`)
+	got, err = spoofed.SetToGoSlice(ctx)
+	if err != nil {
+		t.Fatalf("SetToGoSlice(spoofed iterator): %v", err)
+	}
+	if want := []interface{}{float64(1), float64(2)}; !reflect.DeepEqual(got, want) {
+		t.Errorf("SetToGoSlice(spoofed iterator) = %#v, want %#v", got, want)
+	}
+
+	arr := evalValue(t, ctx, interp, `[1]`)
+	if _, err := arr.SetToGoSlice(ctx); err == nil {
+		t.Error("SetToGoSlice(Array): expected error")
+	}
+}
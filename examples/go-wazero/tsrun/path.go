@@ -0,0 +1,137 @@
+package tsrun
+
+import (
+	"context"
+	"fmt"
+	"math"
+	"strconv"
+	"strings"
+)
+
+// pathSegment is one step of a property path: either a property key or an
+// array index.
+type pathSegment struct {
+	key     string
+	index   int
+	isIndex bool
+}
+
+// String returns the segment as it appears in a path.
+func (s pathSegment) String() string {
+	if s.isIndex {
+		return fmt.Sprintf("[%d]", s.index)
+	}
+	return s.key
+}
+
+// parsePath splits a property path into segments.
+func parsePath(path string) ([]pathSegment, error) {
+	if path == "" {
+		return nil, fmt.Errorf("empty path")
+	}
+
+	var segments []pathSegment
+	for i, part := range strings.Split(path, ".") {
+		// Property key up to the first bracket
+		key := part
+		rest := ""
+		if open := strings.IndexByte(part, '['); open >= 0 {
+			key, rest = part[:open], part[open:]
+		}
+
+		// A key is required except before a leading index ("[0].name")
+		if key != "" {
+			segments = append(segments, pathSegment{key: key})
+		} else if i > 0 || rest == "" {
+			return nil, fmt.Errorf("invalid path %q: empty property name", path)
+		}
+
+		// Zero or more [N] index suffixes
+		for rest != "" {
+			end := strings.IndexByte(rest, ']')
+			if rest[0] != '[' || end < 0 {
+				return nil, fmt.Errorf("invalid path %q: malformed index in %q", path, part)
+			}
+			index, err := strconv.Atoi(rest[1:end])
+			if err != nil {
+				return nil, fmt.Errorf("invalid path %q: bad index %q", path, rest[1:end])
+			}
+			segments = append(segments, pathSegment{index: index, isIndex: true})
+			rest = rest[end+1:]
+		}
+	}
+	return segments, nil
+}
+
+// GetPath retrieves a nested value by following a property path.
+//
+// Segments are separated by dots; array indices use brackets and may be
+// negative to count from the end of an array:
+//
+//	user.name            // v.user.name
+//	data.items[0].name   // v.data.items[0].name
+//	results[-1].score    // last element of v.results
+//	matrix[1][2]         // v.matrix[1][2]
+//	[0].id               // v[0].id
+//
+// Missing values behave like optional chaining (v?.data?.items?.[0]): a
+// missing property or out-of-range index yields undefined, and so does every
+// segment after it. Returns an error if an intermediate value is a
+// primitive other than undefined or null, or for a negative index on a
+// value that is not an array.
+func (v *Value) GetPath(ctx context.Context, path string) (*Value, error) {
+	segments, err := parsePath(path)
+	if err != nil {
+		return nil, err
+	}
+
+	current := v
+	for i, seg := range segments {
+		if current.IsUndefined(ctx) || current.IsNull(ctx) {
+			if current != v {
+				current.Free(ctx)
+			}
+			return v.ctx.Undefined(ctx)
+		}
+
+		next, err := current.getSegment(ctx, seg)
+		if current != v {
+			current.Free(ctx)
+		}
+		if err != nil {
+			return nil, fmt.Errorf("path %q at %s: %w", path, segments[i], err)
+		}
+		current = next
+	}
+	return current, nil
+}
+
+// getSegment retrieves a single path segment from the value.
+func (v *Value) getSegment(ctx context.Context, seg pathSegment) (*Value, error) {
+	if !seg.isIndex {
+		return v.Get(ctx, seg.key)
+	}
+
+	if !v.IsArray(ctx) {
+		if seg.index < 0 {
+			return nil, fmt.Errorf("negative index on non-array value")
+		}
+		return v.Get(ctx, strconv.Itoa(seg.index))
+	}
+
+	index := seg.index
+	if index < 0 {
+		length, err := v.ArrayLength(ctx)
+		if err != nil {
+			return nil, err
+		}
+		index += int(length)
+		if index < 0 {
+			return v.ctx.Undefined(ctx)
+		}
+	}
+	if uint64(index) > math.MaxUint32 {
+		return v.ctx.Undefined(ctx) // Past any possible array length
+	}
+	return v.ArrayGet(ctx, uint32(index))
+}
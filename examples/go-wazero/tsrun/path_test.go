@@ -0,0 +1,112 @@
+package tsrun
+
+import (
+	"reflect"
+	"testing"
+)
+
+func TestParsePath(t *testing.T) {
+	key := func(k string) pathSegment { return pathSegment{key: k} }
+	idx := func(i int) pathSegment { return pathSegment{index: i, isIndex: true} }
+
+	tests := []struct {
+		path string
+		want []pathSegment
+	}{
+		{"name", []pathSegment{key("name")}},
+		{"user.name", []pathSegment{key("user"), key("name")}},
+		{"data.items[0].name", []pathSegment{key("data"), key("items"), idx(0), key("name")}},
+		{"results[-1].score", []pathSegment{key("results"), idx(-1), key("score")}},
+		{"matrix[1][2]", []pathSegment{key("matrix"), idx(1), idx(2)}},
+		{"[0].id", []pathSegment{idx(0), key("id")}},
+	}
+	for _, tt := range tests {
+		got, err := parsePath(tt.path)
+		if err != nil {
+			t.Errorf("parsePath(%q): %v", tt.path, err)
+			continue
+		}
+		if !reflect.DeepEqual(got, tt.want) {
+			t.Errorf("parsePath(%q) = %v, want %v", tt.path, got, tt.want)
+		}
+	}
+}
+
+func TestParsePathErrors(t *testing.T) {
+	for _, path := range []string{"", "a..b", "a.", ".a", "a[", "a[x]", "a[1]b", "a.[0]"} {
+		if _, err := parsePath(path); err == nil {
+			t.Errorf("parsePath(%q): expected error", path)
+		}
+	}
+}
+
+func TestGetPath(t *testing.T) {
+	ctx, interp := newTestContext(t)
+
+	data := evalValue(t, ctx, interp, `({
+		user: { name: "Ann" },
+		items: [{ name: "x" }, { name: "y" }],
+		matrix: [[1, 2], [3, 4]],
+		nothing: null,
+		label: "abc",
+		byKey: { "0": "zero" },
+	})`)
+
+	// "undefined" means the result must be the undefined value; anything
+	// else is compared with the result's JSON
+	tests := []struct {
+		path string
+		want string
+	}{
+		{"user.name", `"Ann"`},
+		{"items[1].name", `"y"`},
+		{"items[-1].name", `"y"`},
+		{"items[-2].name", `"x"`},
+		{"matrix[1][0]", `3`},
+		{"matrix[-1][-1]", `4`},
+		{"byKey[0]", `"zero"`},
+
+		// Out-of-range indices
+		{"items[2]", "undefined"},
+		{"items[-3]", "undefined"},
+		{"items[99].name", "undefined"},
+		{"items[-99].name", "undefined"},
+		{"items[4294967296]", "undefined"}, // Would wrap to 0 as a uint32
+		{"items[-4294967296]", "undefined"},
+
+		// Missing intermediate values
+		{"missing", "undefined"},
+		{"missing.name", "undefined"},
+		{"missing[0].name", "undefined"},
+		{"nothing.name", "undefined"},
+		{"user.address.city", "undefined"},
+	}
+	for _, tt := range tests {
+		got, err := data.GetPath(ctx, tt.path)
+		if err != nil {
+			t.Errorf("GetPath(%q): %v", tt.path, err)
+			continue
+		}
+		if tt.want == "undefined" {
+			if !got.IsUndefined(ctx) {
+				s, _ := interp.JSONStringify(ctx, got)
+				t.Errorf("GetPath(%q) = %s, want undefined", tt.path, s)
+			}
+		} else if s, err := interp.JSONStringify(ctx, got); err != nil || s != tt.want {
+			t.Errorf("GetPath(%q) = %s (%v), want %s", tt.path, s, err, tt.want)
+		}
+		got.Free(ctx)
+	}
+
+	for _, path := range []string{
+		"label.length",    // step into a string
+		"user[-1]",        // negative index on a non-array
+		"items[0].name.x", // step into a string at the end of a chain
+		"a..b",            // invalid path
+	} {
+		if got, err := data.GetPath(ctx, path); err == nil {
+			got.Free(ctx)
+			t.Errorf("GetPath(%q): expected error", path)
+		}
+	}
+}
@@ -39,6 +39,7 @@ type Runtime struct {
 	fnUndefined     api.Function
 	fnObject        api.Function
 	fnArray         api.Function
+	fnErrorNew      api.Function
 	fnGetType       api.Function
 	fnGetNumber     api.Function
 	fnGetString     api.Function
@@ -47,11 +48,18 @@ type Runtime struct {
 	fnIsUndefined   api.Function
 	fnIsArray       api.Function
 	fnIsFunction    api.Function
+	fnIsMap         api.Function
+	fnIsSet         api.Function
+	fnIsGenerator   api.Function
 	fnGet           api.Function
 	fnSet           api.Function
 	fnDelete        api.Function
 	fnHas           api.Function
 	fnKeys          api.Function
+	fnFreeze        api.Function
+	fnSeal          api.Function
+	fnIsFrozen      api.Function
+	fnIsSealed      api.Function
 	fnArrayLength   api.Function
 	fnArrayGet      api.Function
 	fnArraySet      api.Function
@@ -61,6 +69,16 @@ type Runtime struct {
 	fnFreeString    api.Function
 	fnFreeStrings   api.Function
 
+	// Function calls
+	fnCallMethod api.Function
+
+	// Symbol registry
+	fnSymbolFor    api.Function
+	fnSymbolKeyFor api.Function
+
+	// Map and Set entries
+	fnCollectionEntries api.Function
+
 	// Module functions
 	fnProvideModule api.Function
 	fnGetImports    api.Function
@@ -71,6 +89,8 @@ type Runtime struct {
 	fnCreateOrderPromise  api.Function
 	fnResolvePromise      api.Function
 	fnRejectPromise       api.Function
+	fnRejectOrder         api.Function
+	fnRejectPromiseValue  api.Function
 
 	// Native function support
 	fnNativeFunction api.Function
@@ -82,6 +102,10 @@ type Runtime struct {
 	// Console callback
 	consoleCallback func(level ConsoleLevel, message string)
 	consoleMu       sync.Mutex
+
+	// Optional APIs installed in every context
+	broadcastChannelAPI bool
+	abortControllerAPI  bool
 }
 
 // ConsoleOption sets a console callback function.
@@ -265,8 +289,9 @@ func (r *Runtime) getExportedFunctions() error {
 	r.fnNull = r.module.ExportedFunction("tsrun_null")
 	r.fnUndefined = r.module.ExportedFunction("tsrun_undefined")
 	r.fnObject = r.module.ExportedFunction("tsrun_object")
-	r.fnArray = r.module.ExportedFunction("tsrun_array")
-	r.fnGetType = r.module.ExportedFunction("tsrun_get_type")
+	r.fnArray = r.module.ExportedFunction("tsrun_array_new")
+	r.fnErrorNew = r.module.ExportedFunction("tsrun_error_new")
+	r.fnGetType = r.module.ExportedFunction("tsrun_typeof")
 	r.fnGetNumber = r.module.ExportedFunction("tsrun_get_number")
 	r.fnGetString = r.module.ExportedFunction("tsrun_get_string")
 	r.fnGetBool = r.module.ExportedFunction("tsrun_get_bool")
@@ -274,12 +299,19 @@ func (r *Runtime) getExportedFunctions() error {
 	r.fnIsUndefined = r.module.ExportedFunction("tsrun_is_undefined")
 	r.fnIsArray = r.module.ExportedFunction("tsrun_is_array")
 	r.fnIsFunction = r.module.ExportedFunction("tsrun_is_function")
+	r.fnIsMap = r.module.ExportedFunction("tsrun_is_map")
+	r.fnIsSet = r.module.ExportedFunction("tsrun_is_set")
+	r.fnIsGenerator = r.module.ExportedFunction("tsrun_is_generator")
 	r.fnGet = r.module.ExportedFunction("tsrun_get")
 	r.fnSet = r.module.ExportedFunction("tsrun_set")
 	r.fnDelete = r.module.ExportedFunction("tsrun_delete")
 	r.fnHas = r.module.ExportedFunction("tsrun_has")
 	r.fnKeys = r.module.ExportedFunction("tsrun_keys")
-	r.fnArrayLength = r.module.ExportedFunction("tsrun_array_length")
+	r.fnFreeze = r.module.ExportedFunction("tsrun_freeze")
+	r.fnSeal = r.module.ExportedFunction("tsrun_seal")
+	r.fnIsFrozen = r.module.ExportedFunction("tsrun_is_frozen")
+	r.fnIsSealed = r.module.ExportedFunction("tsrun_is_sealed")
+	r.fnArrayLength = r.module.ExportedFunction("tsrun_array_len")
 	r.fnArrayGet = r.module.ExportedFunction("tsrun_array_get")
 	r.fnArraySet = r.module.ExportedFunction("tsrun_array_set")
 	r.fnArrayPush = r.module.ExportedFunction("tsrun_array_push")
@@ -288,6 +320,16 @@ func (r *Runtime) getExportedFunctions() error {
 	r.fnFreeString = r.module.ExportedFunction("tsrun_free_string")
 	r.fnFreeStrings = r.module.ExportedFunction("tsrun_free_strings")
 
+	// Function calls
+	r.fnCallMethod = r.module.ExportedFunction("tsrun_call_method")
+
+	// Symbol registry
+	r.fnSymbolFor = r.module.ExportedFunction("tsrun_symbol_for")
+	r.fnSymbolKeyFor = r.module.ExportedFunction("tsrun_symbol_key_for")
+
+	// Map and Set entries
+	r.fnCollectionEntries = r.module.ExportedFunction("tsrun_collection_entries")
+
 	// Module functions
 	r.fnProvideModule = r.module.ExportedFunction("tsrun_provide_module")
 	r.fnGetImports = r.module.ExportedFunction("tsrun_get_imports")
@@ -298,6 +340,8 @@ func (r *Runtime) getExportedFunctions() error {
 	r.fnCreateOrderPromise = r.module.ExportedFunction("tsrun_create_order_promise")
 	r.fnResolvePromise = r.module.ExportedFunction("tsrun_resolve_promise")
 	r.fnRejectPromise = r.module.ExportedFunction("tsrun_reject_promise")
+	r.fnRejectOrder = r.module.ExportedFunction("tsrun_reject_order")
+	r.fnRejectPromiseValue = r.module.ExportedFunction("tsrun_reject_promise_value")
 
 	// Native function support
 	r.fnNativeFunction = r.module.ExportedFunction("tsrun_native_function")
@@ -116,6 +116,9 @@ type StepResult struct {
 	PendingOrders []Order
 	// CancelledOrders contains cancelled order IDs (for StatusSuspended).
 	CancelledOrders []uint64
+	// CancelledOrderIDs is an alias of CancelledOrders (the same slice),
+	// named to make clear it holds order IDs.
+	CancelledOrderIDs []uint64
 }
 
 // ConsoleLevel represents the log level for console output.
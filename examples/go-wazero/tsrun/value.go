@@ -4,6 +4,9 @@ import (
 	"context"
 	"fmt"
 	"math"
+	"strconv"
+
+	"github.com/tetratelabs/wazero/api"
 )
 
 // Value represents a JavaScript value handle.
@@ -139,6 +142,41 @@ func (v *Value) IsFunction(ctx context.Context) bool {
 	return len(results) > 0 && results[0] != 0
 }
 
+// IsMap returns true if the value is a Map.
+// The check uses the object's internal type, so it cannot be spoofed by
+// changing the prototype or Symbol.toStringTag.
+func (v *Value) IsMap(ctx context.Context) bool {
+	if v.handle == 0 || v.ctx.rt.fnIsMap == nil {
+		return false
+	}
+
+	results, _ := v.ctx.rt.fnIsMap.Call(ctx, uint64(v.handle))
+	return len(results) > 0 && results[0] != 0
+}
+
+// IsSet returns true if the value is a Set.
+// Like IsMap, the check uses the object's internal type.
+func (v *Value) IsSet(ctx context.Context) bool {
+	if v.handle == 0 || v.ctx.rt.fnIsSet == nil {
+		return false
+	}
+
+	results, _ := v.ctx.rt.fnIsSet.Call(ctx, uint64(v.handle))
+	return len(results) > 0 && results[0] != 0
+}
+
+// IsGeneratorObject returns true if the value is a generator object (the
+// result of calling a generator function) that has not yet completed.
+// Returns false for generator functions themselves.
+func (v *Value) IsGeneratorObject(ctx context.Context) bool {
+	if v.handle == 0 || v.ctx.rt.fnIsGenerator == nil {
+		return false
+	}
+
+	results, _ := v.ctx.rt.fnIsGenerator.Call(ctx, uint64(v.handle))
+	return len(results) > 0 && results[0] != 0
+}
+
 // Get retrieves a property from an object.
 func (v *Value) Get(ctx context.Context, key string) (*Value, error) {
 	if v.handle == 0 || v.ctx.rt.fnGet == nil {
@@ -222,6 +260,302 @@ func (v *Value) Set(ctx context.Context, key string, value *Value) error {
 	return nil
 }
 
+// SetByIndex sets the property named by idx, like obj[idx] = value in JS.
+// It works on any object through the general property path, so on an array
+// it writes the element and updates the length. See ArraySet for the
+// array-only fast path.
+func (v *Value) SetByIndex(ctx context.Context, idx uint32, value *Value) error {
+	return v.Set(ctx, strconv.FormatUint(uint64(idx), 10), value)
+}
+
+// Delete removes a property from an object.
+// Like the JS delete operator, deleting a missing property is not an error
+// and deleting an array element leaves a hole (the length is unchanged).
+// Returns an error if the property is non-configurable, including elements
+// of a frozen or sealed array.
+func (v *Value) Delete(ctx context.Context, key string) error {
+	if v.handle == 0 || v.ctx.rt.fnDelete == nil {
+		return fmt.Errorf("value is nil or function not available")
+	}
+
+	keyPtr, err := v.ctx.rt.allocString(ctx, key)
+	if err != nil {
+		return err
+	}
+	defer v.ctx.rt.deallocString(ctx, keyPtr, uint32(len(key)+1))
+
+	// TsRunResult: { ok: bool (4 bytes), error: *c_char (4 bytes) } = 8 bytes
+	const resultSize = 8
+	resultPtr, err := v.ctx.rt.allocResult(ctx, resultSize)
+	if err != nil {
+		return fmt.Errorf("failed to allocate result: %w", err)
+	}
+	defer v.ctx.rt.deallocResult(ctx, resultPtr, resultSize)
+
+	// Call with sret convention: (sret, ctx, obj, key)
+	_, err = v.ctx.rt.fnDelete.Call(ctx, uint64(resultPtr), uint64(v.ctx.handle), uint64(v.handle), uint64(keyPtr))
+	if err != nil {
+		return err
+	}
+
+	// Read TsRunResult from memory
+	okVal, _ := v.ctx.rt.memory.ReadUint32Le(resultPtr)
+	errorPtr, _ := v.ctx.rt.memory.ReadUint32Le(resultPtr + 4)
+
+	if okVal == 0 {
+		return fmt.Errorf("delete error: %s", v.ctx.rt.readString(errorPtr))
+	}
+
+	return nil
+}
+
+// Keys returns the own string property names of an object.
+// Returns nil for non-object values.
+func (v *Value) Keys(ctx context.Context) ([]string, error) {
+	if v.handle == 0 || v.ctx.rt.fnKeys == nil {
+		return nil, fmt.Errorf("value is nil or function not available")
+	}
+
+	// count_out: usize (4 bytes on wasm32)
+	const countSize = 4
+	countPtr, err := v.ctx.rt.allocResult(ctx, countSize)
+	if err != nil {
+		return nil, fmt.Errorf("failed to allocate count: %w", err)
+	}
+	defer v.ctx.rt.deallocResult(ctx, countPtr, countSize)
+
+	// tsrun_keys(ctx, obj, count_out) -> *mut *mut c_char
+	results, err := v.ctx.rt.fnKeys.Call(ctx, uint64(v.ctx.handle), uint64(v.handle), uint64(countPtr))
+	if err != nil {
+		return nil, err
+	}
+
+	keysPtr := uint32(results[0])
+	count, _ := v.ctx.rt.memory.ReadUint32Le(countPtr)
+	if keysPtr == 0 || count == 0 {
+		return nil, nil
+	}
+
+	// Array of C string pointers (4 bytes each on wasm32)
+	keys := make([]string, count)
+	for i := uint32(0); i < count; i++ {
+		strPtr, _ := v.ctx.rt.memory.ReadUint32Le(keysPtr + i*4)
+		keys[i] = v.ctx.rt.readString(strPtr)
+	}
+
+	// Free the strings and the array itself
+	if v.ctx.rt.fnFreeStrings != nil {
+		v.ctx.rt.fnFreeStrings.Call(ctx, uint64(keysPtr), uint64(count))
+	}
+
+	return keys, nil
+}
+
+// ArrayLength returns the number of elements in an array.
+// Returns an error if the value is not an array; use Get(ctx, "length")
+// for the length of strings or array-like objects.
+func (v *Value) ArrayLength(ctx context.Context) (uint32, error) {
+	if v.handle == 0 || v.ctx.rt.fnArrayLength == nil {
+		return 0, fmt.Errorf("value is nil or function not available")
+	}
+
+	if !v.IsArray(ctx) {
+		return 0, fmt.Errorf("value is not an array")
+	}
+
+	// tsrun_array_len(arr) -> usize
+	results, err := v.ctx.rt.fnArrayLength.Call(ctx, uint64(v.handle))
+	if err != nil {
+		return 0, err
+	}
+
+	return uint32(results[0]), nil
+}
+
+// ArrayGet returns an array element by index, or undefined if idx is past
+// the end. Returns an error if the value is not an array.
+//
+// ArrayGet reads directly from the array's element storage. Use Get for
+// general property access on objects, including named array properties.
+func (v *Value) ArrayGet(ctx context.Context, idx uint32) (*Value, error) {
+	if v.handle == 0 || v.ctx.rt.fnArrayGet == nil {
+		return nil, fmt.Errorf("value is nil or function not available")
+	}
+
+	// TsRunValueResult: { value: *TsRunValue (4 bytes), error: *c_char (4 bytes) } = 8 bytes
+	const resultSize = 8
+	resultPtr, err := v.ctx.rt.allocResult(ctx, resultSize)
+	if err != nil {
+		return nil, fmt.Errorf("failed to allocate result: %w", err)
+	}
+	defer v.ctx.rt.deallocResult(ctx, resultPtr, resultSize)
+
+	// Call with sret convention: (sret, ctx, arr, index)
+	_, err = v.ctx.rt.fnArrayGet.Call(ctx, uint64(resultPtr), uint64(v.ctx.handle), uint64(v.handle), uint64(idx))
+	if err != nil {
+		return nil, err
+	}
+
+	// Read TsRunValueResult from memory
+	valuePtr, _ := v.ctx.rt.memory.ReadUint32Le(resultPtr)
+	errorPtr, _ := v.ctx.rt.memory.ReadUint32Le(resultPtr + 4)
+
+	if valuePtr == 0 {
+		return nil, fmt.Errorf("array_get error: %s", v.ctx.rt.readString(errorPtr))
+	}
+
+	return &Value{ctx: v.ctx, handle: valuePtr}, nil
+}
+
+// ArraySet sets an array element by index, extending the array with
+// undefined elements if idx is past the end.
+//
+// Unlike SetByIndex, which converts idx to a property key and goes through
+// the general property path, ArraySet writes directly into the array's
+// element storage. Prefer it when building arrays from Go. Returns an error
+// if the value is not an array.
+func (v *Value) ArraySet(ctx context.Context, idx uint32, val *Value) error {
+	if v.handle == 0 || v.ctx.rt.fnArraySet == nil {
+		return fmt.Errorf("value is nil or function not available")
+	}
+
+	valHandle := uint32(0)
+	if val != nil {
+		valHandle = val.handle
+	}
+
+	// TsRunResult: { ok: bool (4 bytes), error: *c_char (4 bytes) } = 8 bytes
+	const resultSize = 8
+	resultPtr, err := v.ctx.rt.allocResult(ctx, resultSize)
+	if err != nil {
+		return fmt.Errorf("failed to allocate result: %w", err)
+	}
+	defer v.ctx.rt.deallocResult(ctx, resultPtr, resultSize)
+
+	// Call with sret convention: (sret, ctx, arr, index, val)
+	_, err = v.ctx.rt.fnArraySet.Call(ctx, uint64(resultPtr), uint64(v.ctx.handle), uint64(v.handle), uint64(idx), uint64(valHandle))
+	if err != nil {
+		return err
+	}
+
+	// Read TsRunResult from memory
+	okVal, _ := v.ctx.rt.memory.ReadUint32Le(resultPtr)
+	errorPtr, _ := v.ctx.rt.memory.ReadUint32Le(resultPtr + 4)
+
+	if okVal == 0 {
+		return fmt.Errorf("array_set error: %s", v.ctx.rt.readString(errorPtr))
+	}
+
+	return nil
+}
+
+// ArrayPush appends an element to the end of an array.
+func (v *Value) ArrayPush(ctx context.Context, val *Value) error {
+	if v.handle == 0 || v.ctx.rt.fnArrayPush == nil {
+		return fmt.Errorf("value is nil or function not available")
+	}
+
+	valHandle := uint32(0)
+	if val != nil {
+		valHandle = val.handle
+	}
+
+	// TsRunResult: { ok: bool (4 bytes), error: *c_char (4 bytes) } = 8 bytes
+	const resultSize = 8
+	resultPtr, err := v.ctx.rt.allocResult(ctx, resultSize)
+	if err != nil {
+		return fmt.Errorf("failed to allocate result: %w", err)
+	}
+	defer v.ctx.rt.deallocResult(ctx, resultPtr, resultSize)
+
+	// Call with sret convention: (sret, ctx, arr, val)
+	_, err = v.ctx.rt.fnArrayPush.Call(ctx, uint64(resultPtr), uint64(v.ctx.handle), uint64(v.handle), uint64(valHandle))
+	if err != nil {
+		return err
+	}
+
+	// Read TsRunResult from memory
+	okVal, _ := v.ctx.rt.memory.ReadUint32Le(resultPtr)
+	errorPtr, _ := v.ctx.rt.memory.ReadUint32Le(resultPtr + 4)
+
+	if okVal == 0 {
+		return fmt.Errorf("array_push error: %s", v.ctx.rt.readString(errorPtr))
+	}
+
+	return nil
+}
+
+// Freeze makes an object immutable (Object.freeze). Non-object values are
+// left unchanged.
+func (v *Value) Freeze(ctx context.Context) error {
+	return v.setIntegrity(ctx, v.ctx.rt.fnFreeze, "freeze")
+}
+
+// Seal prevents adding or removing properties of an object while keeping
+// existing properties writable (Object.seal). Non-object values are left
+// unchanged.
+func (v *Value) Seal(ctx context.Context) error {
+	return v.setIntegrity(ctx, v.ctx.rt.fnSeal, "seal")
+}
+
+// setIntegrity calls tsrun_freeze or tsrun_seal on the value.
+func (v *Value) setIntegrity(ctx context.Context, fn api.Function, op string) error {
+	if v.handle == 0 || fn == nil {
+		return fmt.Errorf("value is nil or function not available")
+	}
+
+	// TsRunResult: { ok: bool (4 bytes), error: *c_char (4 bytes) } = 8 bytes
+	const resultSize = 8
+	resultPtr, err := v.ctx.rt.allocResult(ctx, resultSize)
+	if err != nil {
+		return fmt.Errorf("failed to allocate result: %w", err)
+	}
+	defer v.ctx.rt.deallocResult(ctx, resultPtr, resultSize)
+
+	// Call with sret convention: (sret, ctx, obj)
+	_, err = fn.Call(ctx, uint64(resultPtr), uint64(v.ctx.handle), uint64(v.handle))
+	if err != nil {
+		return err
+	}
+
+	okVal, _ := v.ctx.rt.memory.ReadUint32Le(resultPtr)
+	errorPtr, _ := v.ctx.rt.memory.ReadUint32Le(resultPtr + 4)
+
+	if okVal == 0 {
+		return fmt.Errorf("%s error: %s", op, v.ctx.rt.readString(errorPtr))
+	}
+
+	return nil
+}
+
+// IsFrozen reports whether an object is frozen (Object.isFrozen).
+// Non-object values are frozen.
+func (v *Value) IsFrozen(ctx context.Context) (bool, error) {
+	if v.handle == 0 || v.ctx.rt.fnIsFrozen == nil {
+		return false, fmt.Errorf("value is nil or function not available")
+	}
+
+	results, err := v.ctx.rt.fnIsFrozen.Call(ctx, uint64(v.handle))
+	if err != nil {
+		return false, err
+	}
+	return len(results) > 0 && results[0] != 0, nil
+}
+
+// IsSealed reports whether an object is sealed (Object.isSealed).
+// Non-object values and frozen objects are sealed.
+func (v *Value) IsSealed(ctx context.Context) (bool, error) {
+	if v.handle == 0 || v.ctx.rt.fnIsSealed == nil {
+		return false, fmt.Errorf("value is nil or function not available")
+	}
+
+	results, err := v.ctx.rt.fnIsSealed.Call(ctx, uint64(v.handle))
+	if err != nil {
+		return false, err
+	}
+	return len(results) > 0 && results[0] != 0, nil
+}
+
 // Context value creation methods
 
 // Number creates a number value.
@@ -230,7 +564,8 @@ func (c *Context) Number(ctx context.Context, n float64) (*Value, error) {
 		return nil, fmt.Errorf("number function not available")
 	}
 
-	results, err := c.rt.fnNumber.Call(ctx, uint64(c.handle), uint64(n))
+	// f64 arguments are passed as their IEEE 754 bit pattern
+	results, err := c.rt.fnNumber.Call(ctx, uint64(c.handle), math.Float64bits(n))
 	if err != nil {
 		return nil, err
 	}
@@ -355,14 +690,26 @@ func (c *Context) Array(ctx context.Context) (*Value, error) {
 		return nil, fmt.Errorf("array function not available")
 	}
 
-	results, err := c.rt.fnArray.Call(ctx, uint64(c.handle))
+	// TsRunValueResult: { value: *TsRunValue (4 bytes), error: *c_char (4 bytes) } = 8 bytes
+	const resultSize = 8
+	resultPtr, err := c.rt.allocResult(ctx, resultSize)
+	if err != nil {
+		return nil, fmt.Errorf("failed to allocate result: %w", err)
+	}
+	defer c.rt.deallocResult(ctx, resultPtr, resultSize)
+
+	// Call with sret convention: (sret, ctx)
+	_, err = c.rt.fnArray.Call(ctx, uint64(resultPtr), uint64(c.handle))
 	if err != nil {
 		return nil, err
 	}
 
-	valuePtr := uint32(results[0])
+	// Read TsRunValueResult from memory
+	valuePtr, _ := c.rt.memory.ReadUint32Le(resultPtr)
+	errorPtr, _ := c.rt.memory.ReadUint32Le(resultPtr + 4)
+
 	if valuePtr == 0 {
-		return nil, fmt.Errorf("failed to create array")
+		return nil, fmt.Errorf("failed to create array: %s", c.rt.readString(errorPtr))
 	}
 
 	return &Value{ctx: c, handle: valuePtr}, nil
@@ -440,3 +787,84 @@ func (c *Context) JSONParse(ctx context.Context, json string) (*Value, error) {
 
 	return &Value{ctx: c, handle: valuePtr}, nil
 }
+
+// SymbolFor returns the symbol registered under key in the global symbol
+// registry, creating it if needed (Symbol.for). The registry is read
+// directly, so scripts replacing the global Symbol do not affect it.
+func (c *Context) SymbolFor(ctx context.Context, key string) (*Value, error) {
+	if c.rt.fnSymbolFor == nil {
+		return nil, fmt.Errorf("symbol_for function not available")
+	}
+
+	keyPtr, err := c.rt.allocString(ctx, key)
+	if err != nil {
+		return nil, err
+	}
+	defer c.rt.deallocString(ctx, keyPtr, uint32(len(key)+1))
+
+	// TsRunValueResult: { value: *TsRunValue (4 bytes), error: *c_char (4 bytes) } = 8 bytes
+	const resultSize = 8
+	resultPtr, err := c.rt.allocResult(ctx, resultSize)
+	if err != nil {
+		return nil, fmt.Errorf("failed to allocate result: %w", err)
+	}
+	defer c.rt.deallocResult(ctx, resultPtr, resultSize)
+
+	// Call with sret convention: (sret, ctx, key)
+	_, err = c.rt.fnSymbolFor.Call(ctx, uint64(resultPtr), uint64(c.handle), uint64(keyPtr))
+	if err != nil {
+		return nil, err
+	}
+
+	valuePtr, _ := c.rt.memory.ReadUint32Le(resultPtr)
+	errorPtr, _ := c.rt.memory.ReadUint32Le(resultPtr + 4)
+
+	if errorPtr != 0 {
+		return nil, fmt.Errorf("symbol_for error: %s", c.rt.readString(errorPtr))
+	}
+
+	return &Value{ctx: c, handle: valuePtr}, nil
+}
+
+// SymbolKeyFor returns the key of a symbol in the global symbol registry
+// (Symbol.keyFor). The boolean is false if the symbol is not registered.
+// Returns an error if the value is not a symbol.
+func (c *Context) SymbolKeyFor(ctx context.Context, symbol *Value) (string, bool, error) {
+	if symbol == nil || symbol.handle == 0 || c.rt.fnSymbolKeyFor == nil {
+		return "", false, fmt.Errorf("value is nil or function not available")
+	}
+
+	// TsRunValueResult: { value: *TsRunValue (4 bytes), error: *c_char (4 bytes) } = 8 bytes
+	const resultSize = 8
+	resultPtr, err := c.rt.allocResult(ctx, resultSize)
+	if err != nil {
+		return "", false, fmt.Errorf("failed to allocate result: %w", err)
+	}
+	defer c.rt.deallocResult(ctx, resultPtr, resultSize)
+
+	// Call with sret convention: (sret, ctx, symbol)
+	_, err = c.rt.fnSymbolKeyFor.Call(ctx, uint64(resultPtr), uint64(c.handle), uint64(symbol.handle))
+	if err != nil {
+		return "", false, err
+	}
+
+	valuePtr, _ := c.rt.memory.ReadUint32Le(resultPtr)
+	errorPtr, _ := c.rt.memory.ReadUint32Le(resultPtr + 4)
+
+	if errorPtr != 0 {
+		return "", false, fmt.Errorf("symbol_key_for error: %s", c.rt.readString(errorPtr))
+	}
+
+	result := &Value{ctx: c, handle: valuePtr}
+	defer result.Free(ctx)
+
+	if result.IsUndefined(ctx) {
+		return "", false, nil
+	}
+
+	key, err := result.AsString(ctx)
+	if err != nil {
+		return "", false, err
+	}
+	return key, true, nil
+}
@@ -0,0 +1,423 @@
+package tsrun
+
+import (
+	"context"
+	"reflect"
+	"sort"
+	"testing"
+)
+
+// newTestContext creates a runtime with opts and an interpreter context for
+// tests. Skips the test if the WASM module has not been built (see build.sh).
+func newTestContext(tb testing.TB, opts ...func(*Runtime)) (context.Context, *Context) {
+	tb.Helper()
+	if len(wasmBytes) == 0 {
+		tb.Skip("tsrun.wasm not built; run ./build.sh --build")
+	}
+
+	ctx := context.Background()
+	rt, err := New(ctx, opts...)
+	if err != nil {
+		tb.Fatalf("New: %v", err)
+	}
+	tb.Cleanup(func() { rt.Close(ctx) })
+
+	interp, err := rt.NewContext(ctx)
+	if err != nil {
+		tb.Fatalf("NewContext: %v", err)
+	}
+	tb.Cleanup(func() { interp.Free(ctx) })
+
+	return ctx, interp
+}
+
+// evalValue runs code in the context and returns its completion value.
+func evalValue(tb testing.TB, ctx context.Context, interp *Context, code string) *Value {
+	tb.Helper()
+	if err := interp.Prepare(ctx, code, "/test.ts"); err != nil {
+		tb.Fatalf("Prepare: %v", err)
+	}
+	result, err := interp.Run(ctx)
+	if err != nil {
+		tb.Fatalf("Run: %v", err)
+	}
+	if result.Status != StatusComplete || result.Value == nil {
+		tb.Fatalf("Run %q: status %s, error %q", code, result.Status, result.Error)
+	}
+	tb.Cleanup(func() { result.Value.Free(ctx) })
+	return result.Value
+}
+
+func TestCollectionPredicates(t *testing.T) {
+	ctx, interp := newTestContext(t)
+
+	tests := []struct {
+		code         string
+		isMap, isSet bool
+	}{
+		{`({})`, false, false},
+		{`[1, 2]`, false, false},
+		{`new Map([["a", 1]])`, true, false},
+		{`new Set([1])`, false, true},
+		{`Object.create(Map.prototype)`, false, false},
+		{`Object.create(Set.prototype)`, false, false},
+		{`({ [Symbol.toStringTag]: "Map" })`, false, false},
+		{`Object.setPrototypeOf(new Map(), Set.prototype)`, true, false},
+		{`Object.setPrototypeOf(new Set(), null)`, false, true},
+	}
+	for _, tt := range tests {
+		v := evalValue(t, ctx, interp, tt.code)
+		if got := v.IsMap(ctx); got != tt.isMap {
+			t.Errorf("IsMap(%s) = %v, want %v", tt.code, got, tt.isMap)
+		}
+		if got := v.IsSet(ctx); got != tt.isSet {
+			t.Errorf("IsSet(%s) = %v, want %v", tt.code, got, tt.isSet)
+		}
+	}
+}
+
+func TestIsGeneratorObject(t *testing.T) {
+	ctx, interp := newTestContext(t)
+
+	const gen = `function* gen() { yield 1; yield 2; }`
+	tests := []struct {
+		code string
+		want bool
+	}{
+		{gen + `; gen`, false},
+		{gen + `; gen()`, true},
+		{gen + `; (() => { const g = gen(); g.next(); return g; })()`, true},
+		{gen + `; (() => { const g = gen(); g.next(); g.next(); g.next(); return g; })()`, false},
+		{`({ next() { return { value: 1, done: false }; } })`, false},
+	}
+	for _, tt := range tests {
+		v := evalValue(t, ctx, interp, tt.code)
+		if got := v.IsGeneratorObject(ctx); got != tt.want {
+			t.Errorf("IsGeneratorObject(%s) = %v, want %v", tt.code, got, tt.want)
+		}
+	}
+}
+
+const benchArrayLen = 1000
+
+func BenchmarkArraySet(b *testing.B) {
+	ctx, interp := newTestContext(b)
+	elem, err := interp.String(ctx, "x")
+	if err != nil {
+		b.Fatal(err)
+	}
+	defer elem.Free(ctx)
+
+	b.ResetTimer()
+	for n := 0; n < b.N; n++ {
+		arr, err := interp.Array(ctx)
+		if err != nil {
+			b.Fatal(err)
+		}
+		for i := uint32(0); i < benchArrayLen; i++ {
+			if err := arr.ArraySet(ctx, i, elem); err != nil {
+				b.Fatal(err)
+			}
+		}
+		arr.Free(ctx)
+	}
+}
+
+func BenchmarkSetByIndex(b *testing.B) {
+	ctx, interp := newTestContext(b)
+	elem, err := interp.String(ctx, "x")
+	if err != nil {
+		b.Fatal(err)
+	}
+	defer elem.Free(ctx)
+
+	b.ResetTimer()
+	for n := 0; n < b.N; n++ {
+		arr, err := interp.Array(ctx)
+		if err != nil {
+			b.Fatal(err)
+		}
+		for i := uint32(0); i < benchArrayLen; i++ {
+			if err := arr.SetByIndex(ctx, i, elem); err != nil {
+				b.Fatal(err)
+			}
+		}
+		arr.Free(ctx)
+	}
+}
+
+func TestSymbolRegistry(t *testing.T) {
+	ctx, interp := newTestContext(t)
+
+	sym, err := interp.SymbolFor(ctx, "app.id")
+	if err != nil {
+		t.Fatalf("SymbolFor: %v", err)
+	}
+	defer sym.Free(ctx)
+	if key, ok, err := interp.SymbolKeyFor(ctx, sym); err != nil || !ok || key != "app.id" {
+		t.Errorf("SymbolKeyFor(SymbolFor) = %q, %v, %v; want \"app.id\", true, nil", key, ok, err)
+	}
+
+	// Registered from JS, read from Go
+	registered := evalValue(t, ctx, interp, `Symbol.for("from.js")`)
+	if key, ok, err := interp.SymbolKeyFor(ctx, registered); err != nil || !ok || key != "from.js" {
+		t.Errorf("SymbolKeyFor(Symbol.for) = %q, %v, %v; want \"from.js\", true, nil", key, ok, err)
+	}
+
+	local := evalValue(t, ctx, interp, `Symbol("app.id")`)
+	if key, ok, err := interp.SymbolKeyFor(ctx, local); err != nil || ok || key != "" {
+		t.Errorf("SymbolKeyFor(Symbol()) = %q, %v, %v; want \"\", false, nil", key, ok, err)
+	}
+
+	str := evalValue(t, ctx, interp, `"app.id"`)
+	if _, _, err := interp.SymbolKeyFor(ctx, str); err == nil {
+		t.Error("SymbolKeyFor(string): expected error")
+	}
+
+	// The registry is read directly, not through the global Symbol
+	evalValue(t, ctx, interp, `(globalThis as any).Symbol = { for: () => 1, keyFor: () => "spoofed" }; 0`)
+	again, err := interp.SymbolFor(ctx, "app.id")
+	if err != nil {
+		t.Fatalf("SymbolFor after replacing Symbol: %v", err)
+	}
+	defer again.Free(ctx)
+	if key, ok, err := interp.SymbolKeyFor(ctx, again); err != nil || !ok || key != "app.id" {
+		t.Errorf("SymbolKeyFor after replacing Symbol = %q, %v, %v; want \"app.id\", true, nil", key, ok, err)
+	}
+}
+
+func TestFreezeAndSeal(t *testing.T) {
+	ctx, interp := newTestContext(t)
+
+	// Writes are attempted from JS after a script has replaced Object
+	const write = `
+		const o = (globalThis as any).target;
+		try { o.a = 2; } catch {}
+		try { o.b = 3; } catch {}
+		try { delete o.c; } catch {}
+		JSON.stringify(o)
+	`
+	tests := []struct {
+		name               string
+		integrity          func(*Value, context.Context) error
+		want               string
+		isFrozen, isSealed bool
+	}{
+		{"Freeze", (*Value).Freeze, `{"a":1,"c":1}`, true, true},
+		{"Seal", (*Value).Seal, `{"a":2,"c":1}`, false, true},
+	}
+	for _, tt := range tests {
+		obj := evalValue(t, ctx, interp, `(globalThis as any).target = { a: 1, c: 1 }; (globalThis as any).Object = undefined; (globalThis as any).target`)
+
+		if frozen, err := obj.IsFrozen(ctx); err != nil || frozen {
+			t.Errorf("%s: IsFrozen before = %v, %v; want false, nil", tt.name, frozen, err)
+		}
+		if err := tt.integrity(obj, ctx); err != nil {
+			t.Fatalf("%s: %v", tt.name, err)
+		}
+
+		got, err := evalValue(t, ctx, interp, write).AsString(ctx)
+		if err != nil || got != tt.want {
+			t.Errorf("%s: object after writes = %q, %v; want %q", tt.name, got, err, tt.want)
+		}
+		if frozen, err := obj.IsFrozen(ctx); err != nil || frozen != tt.isFrozen {
+			t.Errorf("%s: IsFrozen = %v, %v; want %v", tt.name, frozen, err, tt.isFrozen)
+		}
+		if sealed, err := obj.IsSealed(ctx); err != nil || sealed != tt.isSealed {
+			t.Errorf("%s: IsSealed = %v, %v; want %v", tt.name, sealed, err, tt.isSealed)
+		}
+	}
+}
+
+func TestDelete(t *testing.T) {
+	ctx, interp := newTestContext(t)
+
+	tests := []struct {
+		code    string
+		key     string
+		want    string // JSON of the object afterwards
+		wantErr bool
+	}{
+		{`({ a: 1, b: 2 })`, "a", `{"b":2}`, false},
+		{`({ a: 1 })`, "missing", `{"a":1}`, false},
+		{`({ 0: "x", a: 1 })`, "0", `{"a":1}`, false},
+		{`[1, 2, 3]`, "1", `[1,null,3]`, false},
+		{`[1, 2, 3]`, "5", `[1,2,3]`, false},
+		{`Object.defineProperty({ a: 1 }, "k", { value: 1 })`, "k", `{"a":1}`, true},
+		{`Object.freeze({ 0: "x" })`, "0", `{"0":"x"}`, true},
+		{`Object.seal([1, 2])`, "1", `[1,2]`, true},
+	}
+	for _, tt := range tests {
+		obj := evalValue(t, ctx, interp, tt.code)
+		err := obj.Delete(ctx, tt.key)
+		if (err != nil) != tt.wantErr {
+			t.Errorf("Delete(%s, %q) error = %v, want error %v", tt.code, tt.key, err, tt.wantErr)
+		}
+		if got, err := interp.JSONStringify(ctx, obj); err != nil || got != tt.want {
+			t.Errorf("Delete(%s, %q): object = %s (%v), want %s", tt.code, tt.key, got, err, tt.want)
+		}
+	}
+}
+
+func TestKeys(t *testing.T) {
+	ctx, interp := newTestContext(t)
+
+	tests := []struct {
+		code string
+		want []string // Sorted; nil for non-objects and empty objects
+	}{
+		{`({ b: 1, a: 2 })`, []string{"a", "b"}},
+		{`({ 10: "x", 2: "y", "01": "z" })`, []string{"01", "10", "2"}},
+		{`({ a: 1, [Symbol("s")]: 2 })`, []string{"a"}},
+		{`({})`, nil},
+		{`42`, nil},
+		{`"abc"`, nil},
+	}
+	for _, tt := range tests {
+		got, err := evalValue(t, ctx, interp, tt.code).Keys(ctx)
+		if err != nil {
+			t.Errorf("Keys(%s): %v", tt.code, err)
+			continue
+		}
+		sort.Strings(got)
+		if !reflect.DeepEqual(got, tt.want) {
+			t.Errorf("Keys(%s) = %q, want %q", tt.code, got, tt.want)
+		}
+	}
+}
+
+func TestArrayPush(t *testing.T) {
+	ctx, interp := newTestContext(t)
+
+	arr := evalValue(t, ctx, interp, `(globalThis as any).arr = [1]; (globalThis as any).arr`)
+	for _, s := range []string{"a", "b"} {
+		elem, err := interp.String(ctx, s)
+		if err != nil {
+			t.Fatalf("String: %v", err)
+		}
+		err = arr.ArrayPush(ctx, elem)
+		elem.Free(ctx)
+		if err != nil {
+			t.Fatalf("ArrayPush(%q): %v", s, err)
+		}
+	}
+
+	// Read back from JS, so the length and elements are the script's view
+	got, _ := evalValue(t, ctx, interp, `const a = (globalThis as any).arr; a.length + " " + JSON.stringify(a)`).AsString(ctx)
+	if want := `3 [1,"a","b"]`; got != want {
+		t.Errorf("array after ArrayPush = %s, want %s", got, want)
+	}
+
+	obj := evalValue(t, ctx, interp, `({})`)
+	if err := obj.ArrayPush(ctx, arr); err == nil {
+		t.Error("ArrayPush on an object: expected error")
+	}
+}
+
+func TestArraySet(t *testing.T) {
+	ctx, interp := newTestContext(t)
+
+	elem, err := interp.String(ctx, "x")
+	if err != nil {
+		t.Fatalf("String: %v", err)
+	}
+	defer elem.Free(ctx)
+
+	// Overwrite an element, then write past the end; the gap is undefined
+	arr := evalValue(t, ctx, interp, `(globalThis as any).arr = [1, 2]; (globalThis as any).arr`)
+	if err := arr.ArraySet(ctx, 0, elem); err != nil {
+		t.Fatalf("ArraySet(0): %v", err)
+	}
+	if err := arr.ArraySet(ctx, 4, elem); err != nil {
+		t.Fatalf("ArraySet(4): %v", err)
+	}
+	got, _ := evalValue(t, ctx, interp, `const a = (globalThis as any).arr; a.length + " " + JSON.stringify(a) + " " + typeof a[3]`).AsString(ctx)
+	if want := `5 ["x",2,null,null,"x"] undefined`; got != want {
+		t.Errorf("array after ArraySet = %s, want %s", got, want)
+	}
+
+	obj := evalValue(t, ctx, interp, `({})`)
+	if err := obj.ArraySet(ctx, 0, elem); err == nil {
+		t.Error("ArraySet on an object: expected error")
+	}
+}
+
+func TestSetByIndex(t *testing.T) {
+	ctx, interp := newTestContext(t)
+
+	elem, err := interp.String(ctx, "x")
+	if err != nil {
+		t.Fatalf("String: %v", err)
+	}
+	defer elem.Free(ctx)
+
+	// Arrays get an element, other objects an ordinary property
+	for _, tt := range []struct{ code, want string }{
+		{`[1]`, `3 [1,null,"x"]`},
+		{`({ a: 1 })`, `undefined {"2":"x","a":1}`},
+	} {
+		target := evalValue(t, ctx, interp, `(globalThis as any).target = `+tt.code+`; (globalThis as any).target`)
+		if err := target.SetByIndex(ctx, 2, elem); err != nil {
+			t.Fatalf("SetByIndex on %s: %v", tt.code, err)
+		}
+		got, _ := evalValue(t, ctx, interp, `const t = (globalThis as any).target; t.length + " " + JSON.stringify(t)`).AsString(ctx)
+		if got != tt.want {
+			t.Errorf("%s after SetByIndex = %s, want %s", tt.code, got, tt.want)
+		}
+	}
+}
+
+func TestArrayGet(t *testing.T) {
+	ctx, interp := newTestContext(t)
+
+	arr := evalValue(t, ctx, interp, `["a", "b"]`)
+	elem, err := arr.ArrayGet(ctx, 1)
+	if err != nil {
+		t.Fatalf("ArrayGet(1): %v", err)
+	}
+	if got, _ := elem.AsString(ctx); got != "b" {
+		t.Errorf("ArrayGet(1) = %q, want \"b\"", got)
+	}
+	elem.Free(ctx)
+
+	// Past the end is undefined, not nil
+	elem, err = arr.ArrayGet(ctx, 2)
+	if err != nil {
+		t.Fatalf("ArrayGet(2): %v", err)
+	}
+	if !elem.IsUndefined(ctx) {
+		t.Error("ArrayGet(2) is not undefined")
+	}
+	elem.Free(ctx)
+
+	obj := evalValue(t, ctx, interp, `({ 0: "a" })`)
+	if elem, err := obj.ArrayGet(ctx, 0); err == nil {
+		elem.Free(ctx)
+		t.Error("ArrayGet on an object: expected error")
+	}
+}
+
+func TestArrayLength(t *testing.T) {
+	ctx, interp := newTestContext(t)
+
+	for _, tt := range []struct {
+		code string
+		want uint32
+	}{
+		{`[]`, 0},
+		{`[1, 2, 3]`, 3},
+		{`const a: any[] = []; a[9] = 1; a`, 10},
+	} {
+		got, err := evalValue(t, ctx, interp, tt.code).ArrayLength(ctx)
+		if err != nil || got != tt.want {
+			t.Errorf("ArrayLength(%s) = %d, %v; want %d, nil", tt.code, got, err, tt.want)
+		}
+	}
+
+	// Array-likes are not arrays
+	for _, code := range []string{`({ length: 3 })`, `"abc"`} {
+		if got, err := evalValue(t, ctx, interp, code).ArrayLength(ctx); err == nil || got != 0 {
+			t.Errorf("ArrayLength(%s) = %d, %v; want 0 and an error", code, got, err)
+		}
+	}
+}
@@ -4,11 +4,12 @@ extern crate alloc;
 
 use alloc::boxed::Box;
 use alloc::string::ToString;
+use alloc::vec;
 use alloc::vec::Vec;
 use core::ffi::c_char;
 use core::ptr;
 
-use crate::value::{CheapClone, PropertyKey};
+use crate::value::{CheapClone, Guarded, PropertyKey};
 use crate::{JsError, JsString, JsValue, OrderId, OrderResponse, RuntimeValue};
 
 use super::{
@@ -68,6 +69,39 @@ pub extern "C" fn tsrun_fulfill_orders(
     TsRunResult::success()
 }
 
+/// Reject an order with a value, which is thrown at the `order()` call.
+///
+/// Unlike an error string in tsrun_fulfill_orders, the value reaches JS as
+/// is, so hosts can throw Error objects (see tsrun_error_new).
+#[unsafe(no_mangle)]
+pub extern "C" fn tsrun_reject_order(
+    ctx: *mut TsRunContext,
+    order_id: u64,
+    reason: *mut TsRunValue,
+) -> TsRunResult {
+    let ctx = match unsafe { ctx.as_mut() } {
+        Some(c) => c,
+        None => {
+            return TsRunResult {
+                ok: false,
+                error: c"NULL context".as_ptr(),
+            };
+        }
+    };
+
+    let reason_val = match unsafe { reason.as_ref() } {
+        Some(v) => v.value().clone(),
+        None => JsValue::Undefined,
+    };
+
+    let guarded = Guarded::from_value(reason_val, &ctx.interp.heap);
+    ctx.interp.fulfill_orders(vec![OrderResponse {
+        id: OrderId(order_id),
+        result: Err(JsError::thrown(guarded)),
+    }]);
+    TsRunResult::success()
+}
+
 // ============================================================================
 // Pending Order Creation
 // ============================================================================
@@ -245,3 +279,48 @@ pub extern "C" fn tsrun_reject_promise(
         Err(e) => TsRunResult::err(ctx, e.to_string()),
     }
 }
+
+/// Reject a promise created with tsrun_create_order_promise with a value.
+///
+/// Unlike tsrun_reject_promise, the rejection reason is passed through as
+/// is (e.g. an Error object from tsrun_error_new).
+#[unsafe(no_mangle)]
+pub extern "C" fn tsrun_reject_promise_value(
+    ctx: *mut TsRunContext,
+    promise: *mut TsRunValue,
+    reason: *mut TsRunValue,
+) -> TsRunResult {
+    let ctx = match unsafe { ctx.as_mut() } {
+        Some(c) => c,
+        None => {
+            return TsRunResult {
+                ok: false,
+                error: c"NULL context".as_ptr(),
+            };
+        }
+    };
+
+    let promise_val = match unsafe { promise.as_ref() } {
+        Some(v) => v,
+        None => return TsRunResult::err(ctx, "NULL promise".to_string()),
+    };
+
+    let reason_val = match unsafe { reason.as_ref() } {
+        Some(v) => v.value().clone(),
+        None => JsValue::Undefined,
+    };
+
+    let reason_rv = if let JsValue::Object(ref obj) = reason_val {
+        let guard = ctx.interp.heap.create_guard();
+        guard.guard(obj.cheap_clone());
+        RuntimeValue::with_guard(reason_val, guard)
+    } else {
+        RuntimeValue::unguarded(reason_val)
+    };
+
+    let promise_rv = RuntimeValue::unguarded(promise_val.value().clone());
+    match crate::api::reject_promise(&mut ctx.interp, &promise_rv, reason_rv) {
+        Ok(()) => TsRunResult::success(),
+        Err(e) => TsRunResult::err(ctx, e.to_string()),
+    }
+}
@@ -10,7 +10,8 @@ use alloc::vec::Vec;
 use core::ffi::c_char;
 use core::ptr;
 
-use crate::value::{CheapClone, ExoticObject, PropertyKey};
+use crate::interpreter::builtins::object::{object_freeze, object_seal};
+use crate::value::{CheapClone, ExoticObject, GeneratorStatus, JsSymbol, NativeFn, PropertyKey};
 use crate::{JsString, JsValue};
 
 use super::{
@@ -125,6 +126,56 @@ pub extern "C" fn tsrun_is_function(val: *const TsRunValue) -> bool {
         .unwrap_or(false)
 }
 
+/// Check if value is a Map.
+#[unsafe(no_mangle)]
+pub extern "C" fn tsrun_is_map(val: *const TsRunValue) -> bool {
+    unsafe { val.as_ref() }
+        .map(|v| {
+            if let JsValue::Object(obj) = v.value() {
+                matches!(obj.borrow().exotic, ExoticObject::Map { .. })
+            } else {
+                false
+            }
+        })
+        .unwrap_or(false)
+}
+
+/// Check if value is a Set.
+#[unsafe(no_mangle)]
+pub extern "C" fn tsrun_is_set(val: *const TsRunValue) -> bool {
+    unsafe { val.as_ref() }
+        .map(|v| {
+            if let JsValue::Object(obj) = v.value() {
+                matches!(obj.borrow().exotic, ExoticObject::Set { .. })
+            } else {
+                false
+            }
+        })
+        .unwrap_or(false)
+}
+
+/// Check if value is a generator object that has not completed.
+#[unsafe(no_mangle)]
+pub extern "C" fn tsrun_is_generator(val: *const TsRunValue) -> bool {
+    unsafe { val.as_ref() }
+        .map(|v| {
+            if let JsValue::Object(obj) = v.value() {
+                match &obj.borrow().exotic {
+                    ExoticObject::Generator(state) => {
+                        !matches!(state.borrow().status, GeneratorStatus::Completed)
+                    }
+                    ExoticObject::BytecodeGenerator(state) => {
+                        !matches!(state.borrow().status, GeneratorStatus::Completed)
+                    }
+                    _ => false,
+                }
+            } else {
+                false
+            }
+        })
+        .unwrap_or(false)
+}
+
 // ============================================================================
 // Value Extraction
 // ============================================================================
@@ -363,7 +414,7 @@ pub extern "C" fn tsrun_get(
         return TsRunValueResult::err(ctx, "Value is not an object".to_string());
     };
 
-    let prop_key = PropertyKey::String(JsString::from(key_str));
+    let prop_key = ctx.interp.property_key(key_str);
     let value = obj_ref
         .borrow()
         .get_property(&prop_key)
@@ -409,7 +460,7 @@ pub extern "C" fn tsrun_set(
         return TsRunResult::err(ctx, "Value is not an object".to_string());
     };
 
-    let prop_key = PropertyKey::String(JsString::from(key_str));
+    let prop_key = ctx.interp.property_key(key_str);
     obj_ref
         .borrow_mut()
         .set_property(prop_key, val_ref.value().clone());
@@ -424,7 +475,7 @@ pub extern "C" fn tsrun_has(
     obj: *mut TsRunValue,
     key: *const c_char,
 ) -> bool {
-    let _ctx = match unsafe { ctx.as_mut() } {
+    let ctx = match unsafe { ctx.as_mut() } {
         Some(c) => c,
         None => return false,
     };
@@ -443,7 +494,7 @@ pub extern "C" fn tsrun_has(
         return false;
     };
 
-    let prop_key = PropertyKey::String(JsString::from(key_str));
+    let prop_key = ctx.interp.property_key(key_str);
     obj_ref.borrow().get_property(&prop_key).is_some()
 }
 
@@ -478,8 +529,33 @@ pub extern "C" fn tsrun_delete(
         return TsRunResult::err(ctx, "Value is not an object".to_string());
     };
 
-    let prop_key = PropertyKey::String(JsString::from(key_str));
-    obj_ref.borrow_mut().properties.remove(&prop_key);
+    let prop_key = ctx.interp.property_key(key_str);
+    let mut obj_mut = obj_ref.borrow_mut();
+    if let Some(prop) = obj_mut.properties.get(&prop_key)
+        && !prop.configurable()
+    {
+        return TsRunResult::err(
+            ctx,
+            format!("Cannot delete property '{}' of object", key_str),
+        );
+    }
+
+    // Array elements live outside the property map; like the delete
+    // operator, deleting one leaves a hole (undefined)
+    let locked = obj_mut.frozen || obj_mut.sealed;
+    if let PropertyKey::Index(idx) = prop_key
+        && let Some(elements) = obj_mut.array_elements_mut()
+        && let Some(elem) = elements.get_mut(idx as usize)
+    {
+        if locked {
+            return TsRunResult::err(
+                ctx,
+                format!("Cannot delete property '{}' of object", key_str),
+            );
+        }
+        *elem = JsValue::Undefined;
+    }
+    obj_mut.properties.remove(&prop_key);
 
     TsRunResult::success()
 }
@@ -546,6 +622,135 @@ pub extern "C" fn tsrun_keys(
     ptr
 }
 
+// ============================================================================
+// Map and Set
+// ============================================================================
+
+/// Get the entries of a Map or Set as a new array.
+///
+/// A Map gives `[key, value]` pairs and a Set gives its values, both in
+/// insertion order. The entries are read directly, so a script overriding
+/// Symbol.iterator or the prototype methods does not affect the result.
+#[unsafe(no_mangle)]
+pub extern "C" fn tsrun_collection_entries(
+    ctx: *mut TsRunContext,
+    collection: *mut TsRunValue,
+) -> TsRunValueResult {
+    let ctx = match unsafe { ctx.as_mut() } {
+        Some(c) => c,
+        None => {
+            return TsRunValueResult {
+                value: ptr::null_mut(),
+                error: c"NULL context".as_ptr(),
+            };
+        }
+    };
+
+    let coll_val = match unsafe { collection.as_ref() } {
+        Some(v) => v,
+        None => return TsRunValueResult::err(ctx, "NULL collection".to_string()),
+    };
+
+    let JsValue::Object(obj_ref) = coll_val.value() else {
+        return TsRunValueResult::err(ctx, "Value is not a Map or Set".to_string());
+    };
+
+    // Copy the entries out first: creating arrays needs the interpreter.
+    // A Set entry has no value.
+    let entries: Vec<(JsValue, Option<JsValue>)> = match &obj_ref.borrow().exotic {
+        ExoticObject::Map { entries } => entries
+            .iter()
+            .map(|(k, v)| (k.0.clone(), Some(v.clone())))
+            .collect(),
+        ExoticObject::Set { entries } => entries.iter().map(|k| (k.0.clone(), None)).collect(),
+        _ => return TsRunValueResult::err(ctx, "Value is not a Map or Set".to_string()),
+    };
+
+    let guard = ctx.interp.heap.create_guard();
+    let mut elements = Vec::with_capacity(entries.len());
+    for (key, value) in entries {
+        elements.push(match value {
+            Some(value) => JsValue::Object(ctx.interp.create_array_from(&guard, vec![key, value])),
+            None => key,
+        });
+    }
+
+    let arr = ctx.interp.create_array_from(&guard, elements);
+    TsRunValueResult::ok(Box::new(TsRunValue {
+        inner: crate::RuntimeValue::with_guard(JsValue::Object(arr), guard),
+    }))
+}
+
+// ============================================================================
+// Object Integrity
+// ============================================================================
+
+/// Freeze an object, like Object.freeze. Non-objects are left unchanged.
+///
+/// Calls the builtin directly, so it works even if the script has replaced
+/// the global Object.
+#[unsafe(no_mangle)]
+pub extern "C" fn tsrun_freeze(ctx: *mut TsRunContext, obj: *mut TsRunValue) -> TsRunResult {
+    set_integrity(ctx, obj, object_freeze)
+}
+
+/// Seal an object, like Object.seal. Non-objects are left unchanged.
+#[unsafe(no_mangle)]
+pub extern "C" fn tsrun_seal(ctx: *mut TsRunContext, obj: *mut TsRunValue) -> TsRunResult {
+    set_integrity(ctx, obj, object_seal)
+}
+
+/// Shared body of tsrun_freeze and tsrun_seal: calls the given builtin.
+fn set_integrity(ctx: *mut TsRunContext, obj: *mut TsRunValue, builtin: NativeFn) -> TsRunResult {
+    let ctx = match unsafe { ctx.as_mut() } {
+        Some(c) => c,
+        None => {
+            return TsRunResult {
+                ok: false,
+                error: c"NULL context".as_ptr(),
+            };
+        }
+    };
+
+    let obj_val = match unsafe { obj.as_ref() } {
+        Some(v) => v,
+        None => return TsRunResult::err(ctx, "NULL object".to_string()),
+    };
+
+    // The returned guard is dropped at once: obj keeps the object alive
+    let args = [obj_val.value().clone()];
+    match builtin(&mut ctx.interp, JsValue::Undefined, &args) {
+        Ok(_) => TsRunResult::success(),
+        Err(e) => TsRunResult::err(ctx, e.to_string()),
+    }
+}
+
+/// Check if value is frozen, like Object.isFrozen. Non-objects are frozen.
+#[unsafe(no_mangle)]
+pub extern "C" fn tsrun_is_frozen(val: *const TsRunValue) -> bool {
+    unsafe { val.as_ref() }
+        .map(|v| match v.value() {
+            JsValue::Object(obj) => obj.borrow().frozen,
+            _ => true,
+        })
+        .unwrap_or(false)
+}
+
+/// Check if value is sealed, like Object.isSealed. Non-objects and frozen
+/// objects are sealed.
+#[unsafe(no_mangle)]
+pub extern "C" fn tsrun_is_sealed(val: *const TsRunValue) -> bool {
+    unsafe { val.as_ref() }
+        .map(|v| match v.value() {
+            JsValue::Object(obj) => {
+                let obj = obj.borrow();
+                obj.sealed || obj.frozen
+            }
+            _ => true,
+        })
+        .unwrap_or(false)
+}
+
 // ============================================================================
 // Array Operations
 // ============================================================================
@@ -830,6 +1035,48 @@ pub extern "C" fn tsrun_array_new(ctx: *mut TsRunContext) -> TsRunValueResult {
     }))
 }
 
+/// Create an Error object with the given name and message.
+///
+/// The object inherits from the builtin Error.prototype, so `instanceof Error`
+/// holds even if the script has replaced the global Error.
+#[unsafe(no_mangle)]
+pub extern "C" fn tsrun_error_new(
+    ctx: *mut TsRunContext,
+    name: *const c_char,
+    message: *const c_char,
+) -> TsRunValueResult {
+    let ctx = match unsafe { ctx.as_mut() } {
+        Some(c) => c,
+        None => {
+            return TsRunValueResult {
+                value: ptr::null_mut(),
+                error: c"NULL context".as_ptr(),
+            };
+        }
+    };
+
+    let name_str = unsafe { c_str_to_str(name) }.unwrap_or("Error");
+    let message_str = unsafe { c_str_to_str(message) }.unwrap_or("");
+
+    let guard = ctx.interp.heap.create_guard();
+    let error_obj = guard.alloc();
+    {
+        let mut obj_ref = error_obj.borrow_mut();
+        obj_ref.prototype = Some(ctx.interp.error_prototype.cheap_clone());
+        obj_ref.set_property(
+            PropertyKey::String(JsString::from("message")),
+            JsValue::String(JsString::from(message_str)),
+        );
+        obj_ref.set_property(
+            PropertyKey::String(JsString::from("name")),
+            JsValue::String(JsString::from(name_str)),
+        );
+    }
+    TsRunValueResult::ok(Box::new(TsRunValue {
+        inner: crate::RuntimeValue::with_guard(JsValue::Object(error_obj), guard),
+    }))
+}
+
 // ============================================================================
 // Function Calls
 // ============================================================================
@@ -1024,6 +1271,82 @@ pub extern "C" fn tsrun_set_global(
     TsRunResult::success()
 }
 
+// ============================================================================
+// Symbol Registry
+// ============================================================================
+
+/// Get or create the symbol registered under key (Symbol.for).
+///
+/// Reads the interpreter's registry directly, so it is not affected by
+/// scripts that replace the global Symbol.
+#[unsafe(no_mangle)]
+pub extern "C" fn tsrun_symbol_for(ctx: *mut TsRunContext, key: *const c_char) -> TsRunValueResult {
+    let ctx = match unsafe { ctx.as_mut() } {
+        Some(c) => c,
+        None => {
+            return TsRunValueResult {
+                value: ptr::null_mut(),
+                error: c"NULL context".as_ptr(),
+            };
+        }
+    };
+
+    let key_str = match unsafe { c_str_to_str(key) } {
+        Some(s) => JsString::from(s),
+        None => return TsRunValueResult::err(ctx, "Invalid or NULL key".to_string()),
+    };
+
+    let sym = match ctx.interp.symbol_registry_get(&key_str) {
+        Some(sym) => sym,
+        None => {
+            let id = ctx.interp.next_symbol_id();
+            let sym = JsSymbol::new(id, Some(key_str.cheap_clone()));
+            ctx.interp.symbol_registry_insert(key_str, sym.clone());
+            sym
+        }
+    };
+
+    TsRunValueResult::ok(TsRunValue::from_js_value(
+        &mut ctx.interp,
+        JsValue::Symbol(Box::new(sym)),
+    ))
+}
+
+/// Get the registry key of a symbol (Symbol.keyFor).
+///
+/// Returns a string value, or undefined if the symbol is not registered.
+#[unsafe(no_mangle)]
+pub extern "C" fn tsrun_symbol_key_for(
+    ctx: *mut TsRunContext,
+    sym: *const TsRunValue,
+) -> TsRunValueResult {
+    let ctx = match unsafe { ctx.as_mut() } {
+        Some(c) => c,
+        None => {
+            return TsRunValueResult {
+                value: ptr::null_mut(),
+                error: c"NULL context".as_ptr(),
+            };
+        }
+    };
+
+    let sym_val = match unsafe { sym.as_ref() } {
+        Some(v) => v,
+        None => return TsRunValueResult::err(ctx, "NULL symbol".to_string()),
+    };
+
+    let JsValue::Symbol(sym_ref) = sym_val.value() else {
+        return TsRunValueResult::err(ctx, "Value is not a symbol".to_string());
+    };
+
+    let value = match ctx.interp.symbol_registry_key_for(sym_ref.id()) {
+        Some(key) => JsValue::String(key),
+        None => JsValue::Undefined,
+    };
+
+    TsRunValueResult::ok(TsRunValue::from_js_value(&mut ctx.interp, value))
+}
+
 // ============================================================================
 // GC Statistics
 // ============================================================================
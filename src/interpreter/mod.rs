@@ -1149,8 +1149,17 @@ impl Interpreter {
                             self.active_vm = Some(Box::new(vm));
                         }
                         Err(error) => {
-                            // Inject error as exception
+                            // Inject error as exception. Thrown values (e.g. an
+                            // Error object from the host) are passed through as is.
                             let vm_guard = self.heap.create_guard();
+                            let exception = match error {
+                                JsError::ThrownValue { guarded } => guarded.value.clone(),
+                                error => JsValue::String(JsString::from(error.to_string())),
+                            };
+                            if let JsValue::Object(ref obj) = exception {
+                                vm_guard.guard(obj.cheap_clone());
+                            }
+
                             let mut vm = BytecodeVM::from_saved_state(
                                 order_suspension.state,
                                 JsValue::Object(self.global.clone()),
@@ -1158,11 +1167,10 @@ impl Interpreter {
                                 &self.heap,
                             );
 
-                            let error_msg = JsValue::String(JsString::from(error.to_string()));
-                            if vm.inject_exception(self, error_msg.clone()) {
+                            if vm.inject_exception(self, exception.clone()) {
                                 self.active_vm = Some(Box::new(vm));
                             } else {
-                                let guarded = Guarded::from_value(error_msg, &self.heap);
+                                let guarded = Guarded::from_value(exception, &self.heap);
                                 return Err(JsError::thrown(guarded));
                             }
                         }
@@ -7,8 +7,8 @@
 use super::{run, run_to_completion};
 use serde_json::json;
 use tsrun::{
-    InternalModule, Interpreter, InterpreterConfig, JsString, JsValue, OrderId, OrderResponse,
-    RuntimeValue, StepResult, api, create_eval_internal_module, value::PropertyKey,
+    Guarded, InternalModule, Interpreter, InterpreterConfig, JsError, JsString, JsValue, OrderId,
+    OrderResponse, RuntimeValue, StepResult, api, create_eval_internal_module, value::PropertyKey,
 };
 
 // ═══════════════════════════════════════════════════════════════════════════════
@@ -2076,6 +2076,45 @@ fn test_promise_rejection_signals_cancelled_order() {
     }
 }
 
+#[test]
+fn test_order_rejected_with_thrown_value() {
+    // A thrown value in an order response reaches the script unchanged
+    let mut interp = create_test_interp();
+
+    let result = run_with_globals(
+        &mut interp,
+        r#"
+        import { order } from "tsrun:host";
+
+        try {
+            order({ type: "will_fail" });
+            "returned";
+        } catch (e) {
+            e.name + ": " + e.code;
+        }
+    "#,
+    );
+
+    let StepResult::Suspended { pending, .. } = result else {
+        panic!("Expected Suspended");
+    };
+
+    let guard = api::create_guard(&interp);
+    let error = api::create_object(&mut interp, &guard).unwrap();
+    api::set_property(&error, "name", JsValue::from("CancelError")).unwrap();
+    api::set_property(&error, "code", JsValue::from(42)).unwrap();
+    interp.fulfill_orders(vec![OrderResponse {
+        id: pending[0].id,
+        result: Err(JsError::thrown(Guarded::with_guard(error, guard))),
+    }]);
+
+    let result = run_to_completion(&mut interp).unwrap();
+    let StepResult::Complete(value) = result else {
+        panic!("Expected Complete");
+    };
+    assert_eq!(*value, JsValue::String("CancelError: 42".into()));
+}
+
 #[test]
 fn test_three_way_race_cancels_two_losers() {
     // Three-way race: winner gets result, two losers' orders cancelled